@@ -0,0 +1,388 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Onelio/Eldrlang/debugger"
+	"github.com/Onelio/Eldrlang/evaluator"
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+	"io"
+	"io/ioutil"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const threadID = 1
+
+var errNotPaused = errors.New("the program is not paused")
+
+// Server is a Debug Adapter Protocol server running a single
+// Eldr program under the debugger.
+type Server struct {
+	in  *bufio.Reader
+	out io.Writer
+	mu  sync.Mutex
+	seq int
+
+	debugger    *debugger.Debugger
+	program     *parser.Package
+	source      Source
+	stopOnEntry bool
+	configured  bool
+	started     bool
+
+	state     sync.Mutex
+	paused    bool
+	resume    chan debugger.Action
+	stack     []debugger.StackFrame
+	variables map[int]*object.Context
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	s := &Server{
+		in:     bufio.NewReader(in),
+		out:    out,
+		resume: make(chan debugger.Action),
+	}
+	// The protocol may own the process streams, so the program
+	// gets no input and its output is sent as events.
+	eval := evaluator.NewEvaluator()
+	eval.Stdin = strings.NewReader("")
+	eval.Stdout = &output{server: s, category: "stdout"}
+	eval.Stderr = &output{server: s, category: "stderr"}
+	s.debugger = debugger.NewDebugger(eval, s)
+	return s
+}
+
+type output struct {
+	server   *Server
+	category string
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.server.Output(o.category, string(p))
+	return len(p), nil
+}
+
+// Serve handles requests until the client disconnects or
+// closes the input stream.
+func (s *Server) Serve() error {
+	for {
+		req, err := s.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		body, after, err := s.handle(req)
+		res := &response{
+			Type:       "response",
+			RequestSeq: req.Seq,
+			Success:    err == nil,
+			Command:    req.Command,
+			Body:       body,
+		}
+		if err != nil {
+			res.Message = err.Error()
+		}
+		if err := s.send(res); err != nil {
+			return err
+		}
+		// Actions that may emit events run after the response
+		// so that clients always see them in order.
+		if after != nil {
+			after()
+		}
+		if req.Command == "disconnect" {
+			return nil
+		}
+	}
+}
+
+// Output forwards program output to the client.
+func (s *Server) Output(category, output string) {
+	_ = s.event("output", map[string]string{"category": category, "output": output})
+}
+
+func (s *Server) handle(req *request) (interface{}, func(), error) {
+	switch req.Command {
+	case "initialize":
+		return Capabilities{
+			SupportsConfigurationDoneRequest: true,
+			SupportsConditionalBreakpoints:   true,
+			SupportsEvaluateForHovers:        true,
+			SupportsTerminateRequest:         true,
+		}, func() { _ = s.event("initialized", nil) }, nil
+	case "launch":
+		var args LaunchArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, nil, err
+		}
+		if err := s.load(args.Program); err != nil {
+			return nil, nil, err
+		}
+		s.stopOnEntry = args.StopOnEntry
+		return nil, s.start, nil
+	case "configurationDone":
+		s.configured = true
+		return nil, s.start, nil
+	case "setBreakpoints":
+		var args SetBreakpointsArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, nil, err
+		}
+		return map[string]interface{}{"breakpoints": s.setBreakpoints(args.Breakpoints)}, nil, nil
+	case "setExceptionBreakpoints":
+		return nil, nil, nil
+	case "threads":
+		return map[string]interface{}{"threads": []Thread{{ID: threadID, Name: "main"}}}, nil, nil
+	case "stackTrace":
+		return s.stackTrace()
+	case "scopes":
+		var args struct {
+			FrameID int `json:"frameId"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, nil, err
+		}
+		return s.scopes(args.FrameID)
+	case "variables":
+		var args struct {
+			VariablesReference int `json:"variablesReference"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, nil, err
+		}
+		return s.listVariables(args.VariablesReference)
+	case "evaluate":
+		var args EvaluateArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, nil, err
+		}
+		return s.evaluate(args.Expression)
+	case "continue":
+		return s.step(debugger.Continue, map[string]bool{"allThreadsContinued": true})
+	case "next":
+		return s.step(debugger.StepOver, nil)
+	case "stepIn":
+		return s.step(debugger.StepIn, nil)
+	case "stepOut":
+		return s.step(debugger.StepOut, nil)
+	case "disconnect", "terminate":
+		if s.isPaused() {
+			return nil, func() { s.resume <- debugger.Quit }, nil
+		}
+		return nil, nil, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported request %q", req.Command)
+}
+
+func (s *Server) load(path string) error {
+	code, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	pkg := parser.NewParser().ParsePackage(string(code), "main")
+	if pkg.Errors.Len() > 0 {
+		return errors.New(pkg.Errors.String())
+	}
+	s.program = pkg
+	s.source = Source{Name: filepath.Base(path), Path: path}
+	return nil
+}
+
+// start runs the program once it has been both launched and
+// configured, in whichever order those requests arrive.
+func (s *Server) start() {
+	if s.started || !s.configured || s.program == nil {
+		return
+	}
+	s.started = true
+	go func() {
+		out, err := s.debugger.Run(s.program, s.stopOnEntry)
+		exitCode := 0
+		if err == nil && out.Errors.Len() > 0 {
+			s.Output("stderr", out.Errors.String())
+			exitCode = 1
+		}
+		_ = s.event("exited", map[string]int{"exitCode": exitCode})
+		_ = s.event("terminated", nil)
+	}()
+}
+
+// Paused implements debugger.Frontend, blocking the evaluation
+// until the client asks to resume.
+func (s *Server) Paused(d *debugger.Debugger, reason string, node parser.Node) debugger.Action {
+	s.state.Lock()
+	s.paused = true
+	s.stack = d.Stack()
+	s.variables = make(map[int]*object.Context)
+	s.state.Unlock()
+
+	_ = s.event("stopped", map[string]interface{}{
+		"reason":            reason,
+		"threadId":          threadID,
+		"allThreadsStopped": true,
+	})
+	action := <-s.resume
+
+	s.state.Lock()
+	s.paused = false
+	s.state.Unlock()
+	return action
+}
+
+func (s *Server) isPaused() bool {
+	s.state.Lock()
+	defer s.state.Unlock()
+	return s.paused
+}
+
+func (s *Server) step(action debugger.Action, body interface{}) (interface{}, func(), error) {
+	if !s.isPaused() {
+		return nil, nil, errNotPaused
+	}
+	return body, func() { s.resume <- action }, nil
+}
+
+func (s *Server) setBreakpoints(requested []SourceBreakpoint) []Breakpoint {
+	s.debugger.ClearBreakpoints()
+	breakpoints := []Breakpoint{}
+	for _, sb := range requested {
+		bp, err := s.debugger.SetBreakpoint(sb.Line, sb.Condition)
+		if err != nil {
+			breakpoints = append(breakpoints, Breakpoint{Line: sb.Line, Message: err.Error()})
+			continue
+		}
+		breakpoints = append(breakpoints, Breakpoint{ID: bp.ID, Verified: true, Line: bp.Line})
+	}
+	return breakpoints
+}
+
+func (s *Server) stackTrace() (interface{}, func(), error) {
+	s.state.Lock()
+	defer s.state.Unlock()
+	if !s.paused {
+		return nil, nil, errNotPaused
+	}
+	frames := []StackFrame{}
+	for i, frame := range s.stack {
+		frames = append(frames, StackFrame{
+			ID:     i,
+			Name:   frame.Name,
+			Source: s.source,
+			Line:   frame.Line,
+			Column: 1,
+		})
+	}
+	return map[string]interface{}{"stackFrames": frames, "totalFrames": len(frames)}, nil, nil
+}
+
+// scopes lists one scope per context level reachable from the
+// frame, the innermost being its locals.
+func (s *Server) scopes(frameID int) (interface{}, func(), error) {
+	s.state.Lock()
+	defer s.state.Unlock()
+	if !s.paused {
+		return nil, nil, errNotPaused
+	}
+	if frameID < 0 || frameID >= len(s.stack) {
+		return nil, nil, fmt.Errorf("unknown frame %d", frameID)
+	}
+	scopes := []Scope{}
+	contexts := s.stack[frameID].Scopes()
+	for i, ctx := range contexts {
+		name := "Context " + strconv.Itoa(i)
+		switch {
+		case i == len(contexts)-1:
+			name = "Global"
+		case i == 0:
+			name = "Locals"
+		}
+		ref := len(s.variables) + 1
+		s.variables[ref] = ctx
+		scopes = append(scopes, Scope{Name: name, VariablesReference: ref})
+	}
+	return map[string]interface{}{"scopes": scopes}, nil, nil
+}
+
+func (s *Server) listVariables(ref int) (interface{}, func(), error) {
+	s.state.Lock()
+	defer s.state.Unlock()
+	ctx, ok := s.variables[ref]
+	if !s.paused || !ok {
+		return nil, nil, fmt.Errorf("unknown variables reference %d", ref)
+	}
+	variables := []Variable{}
+	for _, name := range ctx.Names() {
+		variables = append(variables, Variable{Name: name, Value: inspect(ctx.Get(name))})
+	}
+	return map[string]interface{}{"variables": variables}, nil, nil
+}
+
+func (s *Server) evaluate(expr string) (interface{}, func(), error) {
+	if !s.isPaused() {
+		return nil, nil, errNotPaused
+	}
+	result, err := s.debugger.Evaluate(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return map[string]interface{}{"result": inspect(result), "variablesReference": 0}, nil, nil
+}
+
+func (s *Server) read() (*request, error) {
+	header, err := textproto.NewReader(s.in).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %v", err)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, err
+	}
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (s *Server) event(name string, body interface{}) error {
+	return s.send(&event{Type: "event", Event: name, Body: body})
+}
+
+func (s *Server) send(msg interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	switch m := msg.(type) {
+	case *response:
+		m.Seq = s.seq
+	case *event:
+		m.Seq = s.seq
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
+
+func inspect(obj object.Object) string {
+	if obj == nil {
+		return "null"
+	}
+	return obj.Inspect()
+}
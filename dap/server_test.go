@@ -0,0 +1,78 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/textproto"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the recorded transcript")
+
+// TestTranscript replays the client messages ("->" lines) of a
+// recorded session and checks that the server answers exactly
+// with the recorded ones ("<-" lines).
+func TestTranscript(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/session.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cin, sin := io.Pipe()
+	sout, cout := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(cin, cout).Serve()
+		_ = cout.Close()
+	}()
+	out := bufio.NewReader(sout)
+
+	var recorded []string
+	for i, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "-> "):
+			body := strings.TrimPrefix(line, "-> ")
+			_, _ = fmt.Fprintf(sin, "Content-Length: %d\r\n\r\n%s", len(body), body)
+			recorded = append(recorded, line)
+		case strings.HasPrefix(line, "<- "):
+			received := receive(t, out)
+			if *update {
+				recorded = append(recorded, "<- "+received)
+				continue
+			}
+			var expected, got interface{}
+			_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "<- ")), &expected)
+			_ = json.Unmarshal([]byte(received), &got)
+			if !reflect.DeepEqual(expected, got) {
+				t.Fatalf("line %d: expected\n%s\ngot\n%s", i+1, line[3:], received)
+			}
+		default:
+			recorded = append(recorded, line)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		_ = ioutil.WriteFile("testdata/session.txt", []byte(strings.Join(recorded, "\n")+"\n"), 0644)
+	}
+}
+
+func receive(t *testing.T, out *bufio.Reader) string {
+	header, err := textproto.NewReader(out).ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	length, _ := strconv.Atoi(header.Get("Content-Length"))
+	body := make([]byte, length)
+	if _, err := io.ReadFull(out, body); err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
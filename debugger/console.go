@@ -0,0 +1,144 @@
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const consoleHelp = `Commands:
+  break LINE [if EXPR]  set a breakpoint, optionally conditional
+  delete ID             remove a breakpoint
+  continue (c)          resume until the next breakpoint
+  step (s)              step into the next statement
+  next (n)              step over function calls
+  out (o)               run until the current function returns
+  stack (bt)            print the call stack
+  vars                  print the variables of every context
+  print EXPR (p)        evaluate an expression
+  set NAME = EXPR       modify a variable
+  quit (q)              stop debugging`
+
+// Console is a line based Frontend reading commands from a
+// reader and printing to a writer.
+type Console struct {
+	input  *bufio.Scanner
+	output io.Writer
+	source []string
+}
+
+func NewConsole(in io.Reader, out io.Writer, source string) *Console {
+	return &Console{
+		input:  bufio.NewScanner(in),
+		output: out,
+		source: strings.Split(source, "\n"),
+	}
+}
+
+func (c *Console) Paused(d *Debugger, reason string, node parser.Node) Action {
+	line := d.Line()
+	c.printf("Stopped (%s) at L%d: %s\n", reason, line, c.sourceLine(line))
+	for {
+		c.printf("(eldr) ")
+		if !c.input.Scan() {
+			return Quit
+		}
+		if action, resume := c.execute(d, strings.TrimSpace(c.input.Text())); resume {
+			return action
+		}
+	}
+}
+
+func (c *Console) execute(d *Debugger, command string) (Action, bool) {
+	name, args := command, ""
+	if i := strings.IndexByte(command, ' '); i > 0 {
+		name, args = command[:i], strings.TrimSpace(command[i+1:])
+	}
+	switch name {
+	case "c", "continue":
+		return Continue, true
+	case "s", "step":
+		return StepIn, true
+	case "n", "next":
+		return StepOver, true
+	case "o", "out":
+		return StepOut, true
+	case "q", "quit":
+		return Quit, true
+	case "b", "break":
+		lineArg, condition := args, ""
+		if i := strings.Index(args, " if "); i > 0 {
+			lineArg, condition = args[:i], args[i+4:]
+		}
+		line, err := strconv.Atoi(strings.TrimSpace(lineArg))
+		if err != nil {
+			c.printf("invalid line %q\n", lineArg)
+			break
+		}
+		bp, err := d.SetBreakpoint(line, condition)
+		if err != nil {
+			c.printf("invalid condition: %s\n", err)
+			break
+		}
+		c.printf("Breakpoint %d at L%d\n", bp.ID, bp.Line)
+	case "delete":
+		id, _ := strconv.Atoi(args)
+		if !d.RemoveBreakpoint(id) {
+			c.printf("no breakpoint %q\n", args)
+		}
+	case "bt", "stack":
+		for i, frame := range d.Stack() {
+			c.printf("#%d %s at L%d\n", i, frame.Name, frame.Line)
+		}
+	case "vars":
+		for i, ctx := range d.Scopes() {
+			c.printf("context %d:\n", i)
+			for _, name := range ctx.Names() {
+				c.printf("  %s = %s\n", name, inspect(ctx.Get(name)))
+			}
+		}
+	case "p", "print":
+		result, err := d.Evaluate(args)
+		if err != nil {
+			c.printf("error: %s\n", err)
+			break
+		}
+		c.printf("%s\n", inspect(result))
+	case "set":
+		parts := strings.SplitN(args, "=", 2)
+		if len(parts) != 2 {
+			c.printf("usage: set NAME = EXPR\n")
+			break
+		}
+		if err := d.Set(strings.TrimSpace(parts[0]), parts[1]); err != nil {
+			c.printf("error: %s\n", err)
+		}
+	case "", "h", "help":
+		c.printf("%s\n", consoleHelp)
+	default:
+		c.printf("unknown command %q, type help\n", name)
+	}
+	return Continue, false
+}
+
+func (c *Console) sourceLine(line int) string {
+	if line < 1 || line > len(c.source) {
+		return ""
+	}
+	return strings.TrimSpace(c.source[line-1])
+}
+
+func (c *Console) printf(format string, a ...interface{}) {
+	_, _ = fmt.Fprintf(c.output, format, a...)
+}
+
+func inspect(obj object.Object) string {
+	if obj == nil {
+		return "null"
+	}
+	return obj.Inspect()
+}
@@ -0,0 +1,258 @@
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Onelio/Eldrlang/evaluator"
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+	"strings"
+	"sync"
+)
+
+type Action int
+
+const (
+	Continue Action = iota
+	StepIn
+	StepOver
+	StepOut
+	Quit
+)
+
+const (
+	ReasonEntry      = "entry"
+	ReasonStep       = "step"
+	ReasonBreakpoint = "breakpoint"
+)
+
+var errQuit = errors.New("debugging session ended")
+
+// Frontend drives a debugging session. Paused is called from the
+// evaluating goroutine every time execution stops and blocks until
+// the frontend decides how to resume.
+type Frontend interface {
+	Paused(d *Debugger, reason string, node parser.Node) Action
+}
+
+type Breakpoint struct {
+	ID        int
+	Line      int
+	Condition string
+	cond      parser.Node
+}
+
+type Debugger struct {
+	eval        *evaluator.Evaluator
+	frontend    Frontend
+	mu          sync.Mutex
+	breakpoints []*Breakpoint
+	lastID      int
+	action      Action
+	depth       int
+	node        parser.Node
+}
+
+func NewDebugger(eval *evaluator.Evaluator, frontend Frontend) *Debugger {
+	d := &Debugger{eval: eval, frontend: frontend}
+	eval.SetHook(d.statement)
+	return d
+}
+
+// Run evaluates the package under the debugger. When stopOnEntry is
+// set, execution pauses before the first statement. The files left
+// open by the program are closed once it ends.
+func (d *Debugger) Run(pkg *parser.Package, stopOnEntry bool) (out *evaluator.Output, err error) {
+	d.action = Continue
+	if stopOnEntry {
+		d.action = StepIn
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			halt, ok := r.(evaluator.Halt)
+			if !ok {
+				panic(r)
+			}
+			err = halt.Reason
+		}
+		d.node = nil
+		d.eval.Close()
+	}()
+	return d.eval.Evaluate(pkg), nil
+}
+
+// SetBreakpoint adds a breakpoint at a 1-based line. The condition
+// is an Eldr expression and may be empty.
+func (d *Debugger) SetBreakpoint(line int, condition string) (*Breakpoint, error) {
+	bp := &Breakpoint{Line: line, Condition: condition}
+	if condition != "" {
+		node, err := parseExpression(condition)
+		if err != nil {
+			return nil, err
+		}
+		bp.cond = node
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastID++
+	bp.ID = d.lastID
+	d.breakpoints = append(d.breakpoints, bp)
+	return bp, nil
+}
+
+func (d *Debugger) RemoveBreakpoint(id int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, bp := range d.breakpoints {
+		if bp.ID == id {
+			d.breakpoints = append(d.breakpoints[:i], d.breakpoints[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Debugger) ClearBreakpoints() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.breakpoints = nil
+}
+
+func (d *Debugger) Breakpoints() []*Breakpoint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]*Breakpoint{}, d.breakpoints...)
+}
+
+// Line returns the 1-based line execution is paused at.
+func (d *Debugger) Line() int {
+	if d.node == nil {
+		return 0
+	}
+	return parser.TokenOf(d.node).Line + 1
+}
+
+// Stack returns the call stack, innermost frame first. Each entry
+// is paired with the 1-based line and the innermost context it is
+// currently at.
+func (d *Debugger) Stack() []StackFrame {
+	var (
+		frames = d.eval.Frames()
+		stack  = []StackFrame{}
+		line   = d.Line()
+		ctx    = d.eval.Context()
+	)
+	for i := len(frames) - 1; i >= 0; i-- {
+		stack = append(stack, StackFrame{Name: frames[i].Name, Line: line, Context: ctx})
+		line = frames[i].Call.Line + 1
+		ctx = frames[i].Context.Parent()
+	}
+	return append(stack, StackFrame{Name: "main", Line: line, Context: ctx})
+}
+
+type StackFrame struct {
+	Name    string
+	Line    int
+	Context *object.Context
+}
+
+// Scopes returns every context reachable from the frame,
+// innermost first.
+func (f StackFrame) Scopes() []*object.Context {
+	var scopes []*object.Context
+	for ctx := f.Context; ctx != nil; ctx = ctx.Parent() {
+		scopes = append(scopes, ctx)
+	}
+	return scopes
+}
+
+// Scopes returns the contexts reachable from the current one.
+func (d *Debugger) Scopes() []*object.Context {
+	return d.Stack()[0].Scopes()
+}
+
+// Evaluate runs an Eldr expression in the paused context.
+func (d *Debugger) Evaluate(expr string) (object.Object, error) {
+	node, err := parseExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	result, errs := d.eval.EvaluateExpression(node)
+	if errs.Len() > 0 {
+		return nil, errors.New(errs[0].Message())
+	}
+	return result, nil
+}
+
+// Set assigns the result of an expression to the closest
+// existing variable with that name.
+func (d *Debugger) Set(name, expr string) error {
+	value, err := d.Evaluate(expr)
+	if err != nil {
+		return err
+	}
+	for _, ctx := range d.Scopes() {
+		if ctx.Get(name) != nil {
+			ctx.Set(name, value)
+			return nil
+		}
+	}
+	return fmt.Errorf("variable %q not found", name)
+}
+
+func (d *Debugger) statement(node parser.Node) {
+	depth := len(d.eval.Frames())
+	reason := ""
+	switch {
+	case d.action == StepIn && d.node == nil:
+		reason = ReasonEntry
+	case d.action == StepIn:
+		reason = ReasonStep
+	case d.action == StepOver && depth <= d.depth:
+		reason = ReasonStep
+	case d.action == StepOut && depth < d.depth:
+		reason = ReasonStep
+	}
+	if reason == "" && d.hitBreakpoint(node) {
+		reason = ReasonBreakpoint
+	}
+	if reason == "" {
+		return
+	}
+	d.node, d.depth = node, depth
+	d.action = d.frontend.Paused(d, reason, node)
+	if d.action == Quit {
+		panic(evaluator.Halt{Reason: errQuit})
+	}
+}
+
+func (d *Debugger) hitBreakpoint(node parser.Node) bool {
+	line := parser.TokenOf(node).Line + 1
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, bp := range d.breakpoints {
+		if bp.Line != line {
+			continue
+		}
+		if bp.cond == nil {
+			return true
+		}
+		result, errs := d.eval.EvaluateExpression(bp.cond)
+		if b, ok := result.(*object.Boolean); ok && errs.Len() == 0 && b.Value {
+			return true
+		}
+	}
+	return false
+}
+
+func parseExpression(expr string) (parser.Node, error) {
+	expr = strings.TrimSuffix(strings.TrimSpace(expr), ";")
+	pkg := parser.NewParser().ParsePackage(expr+";", "debug")
+	if pkg.Errors.Len() > 0 {
+		return nil, errors.New(pkg.Errors[0].Message())
+	}
+	if len(pkg.Nodes) != 1 {
+		return nil, fmt.Errorf("invalid expression %q", expr)
+	}
+	return pkg.Nodes[0], nil
+}
@@ -0,0 +1,106 @@
+package debugger
+
+import (
+	"fmt"
+	"github.com/Onelio/Eldrlang/evaluator"
+	"github.com/Onelio/Eldrlang/parser"
+	"strings"
+	"testing"
+)
+
+type script func(d *Debugger, reason string) Action
+
+func (s script) Paused(d *Debugger, reason string, node parser.Node) Action {
+	return s(d, reason)
+}
+
+func TestDebuggerSession(t *testing.T) {
+	var code = `var total = 0;
+fun add(a, b) {
+	var sum = a + b;
+	return sum;
+}
+total = add(1, 2);
+total = add(total, 3);
+`
+	var (
+		stops []string
+		steps = []func(d *Debugger) Action{
+			func(d *Debugger) Action { return StepOver },
+			func(d *Debugger) Action { return StepOver },
+			func(d *Debugger) Action { return StepIn },
+			func(d *Debugger) Action {
+				stack := d.Stack()
+				if len(stack) != 2 || stack[0].Name != "add" || stack[1].Line != 6 {
+					t.Fatalf("unexpected stack %+v", stack)
+				}
+				return StepOut
+			},
+			func(d *Debugger) Action {
+				if _, err := d.SetBreakpoint(3, "a == 3"); err != nil {
+					t.Fatal(err)
+				}
+				return Continue
+			},
+			func(d *Debugger) Action {
+				if err := d.Set("b", "7 + 3"); err != nil {
+					t.Fatal(err)
+				}
+				if len(d.Scopes()) != 3 || d.Scopes()[1].Get("a") == nil {
+					t.Fatalf("expected block, function and global contexts")
+				}
+				return Continue
+			},
+		}
+	)
+	frontend := script(func(d *Debugger, reason string) Action {
+		stops = append(stops, fmt.Sprintf("%s:%d", reason, d.Line()))
+		step := steps[0]
+		steps = steps[1:]
+		return step(d)
+	})
+	pkg := parser.NewParser().ParsePackage(code, "main")
+	eval := evaluator.NewEvaluator()
+	if _, err := NewDebugger(eval, frontend).Run(pkg, true); err != nil {
+		t.Fatal(err)
+	}
+	expected := "entry:1 step:2 step:6 step:3 step:7 breakpoint:3"
+	if strings.Join(stops, " ") != expected {
+		t.Fatalf("expected stops %q got %q", expected, strings.Join(stops, " "))
+	}
+	if total := eval.GetValue("total").Inspect(); total != "13" {
+		t.Fatalf("expected modified total 13 got %s", total)
+	}
+}
+
+func TestConsole(t *testing.T) {
+	var code = `var x = 1;
+x = x + 1;
+x = x + 1;
+`
+	var (
+		input  = strings.NewReader("break 3 if x == 2\nc\np x * 10\nset x = 40\nvars\nbt\nc\n")
+		output strings.Builder
+		eval   = evaluator.NewEvaluator()
+	)
+	pkg := parser.NewParser().ParsePackage(code, "main")
+	d := NewDebugger(eval, NewConsole(input, &output, code))
+	if _, err := d.Run(pkg, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"Stopped (entry) at L1: var x = 1;",
+		"Breakpoint 1 at L3",
+		"Stopped (breakpoint) at L3: x = x + 1;",
+		"20\n",
+		"  x = 40\n",
+		"#0 main at L3",
+	} {
+		if !strings.Contains(output.String(), expected) {
+			t.Fatalf("missing %q in output:\n%s", expected, output.String())
+		}
+	}
+	if x := eval.GetValue("x").Inspect(); x != "41" {
+		t.Fatalf("expected x 41 got %s", x)
+	}
+}
@@ -0,0 +1,105 @@
+package eldr
+
+import (
+	"fmt"
+	"github.com/Onelio/Eldrlang/object"
+	"math/big"
+	"reflect"
+)
+
+// ToObject converts Go booleans, integers, strings, slices, arrays
+// and maps to their Eldr counterpart. Eldr objects are kept as is.
+func ToObject(value interface{}) (object.Object, error) {
+	switch v := value.(type) {
+	case nil:
+		return &object.Null{}, nil
+	case object.Object:
+		return v, nil
+	case bool:
+		return &object.Boolean{Value: v}, nil
+	case string:
+		return &object.String{Value: v}, nil
+	case int64:
+		return &object.Integer{Value: v}, nil
+	case *big.Int:
+		return object.FromBig(new(big.Int).Set(v)), nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &object.Integer{Value: rv.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return object.FromBig(new(big.Int).SetUint64(rv.Uint())), nil
+	case reflect.Slice, reflect.Array:
+		array := &object.Array{Elements: make([]object.Object, rv.Len())}
+		for i := 0; i < rv.Len(); i++ {
+			elem, err := ToObject(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			array.Elements[i] = elem
+		}
+		return array, nil
+	case reflect.Map:
+		hash := object.NewMap()
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, err := ToObject(iter.Key().Interface())
+			if err != nil {
+				return nil, err
+			}
+			elem, err := ToObject(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			if !hash.Set(key, elem) {
+				return nil, fmt.Errorf("unsupported map key type %T", iter.Key().Interface())
+			}
+		}
+		return hash, nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", value)
+}
+
+// FromObject converts an Eldr object to a Go value: int64, *big.Int,
+// string, bool, []interface{}, map[interface{}]interface{} or nil. Other
+// objects such as functions are returned unchanged.
+func FromObject(obj object.Object) interface{} {
+	switch o := obj.(type) {
+	case nil, *object.Null:
+		return nil
+	case *object.Integer:
+		return o.Value
+	case *object.BigInt:
+		return new(big.Int).Set(o.Value)
+	case *object.String:
+		return o.Value
+	case *object.Boolean:
+		return o.Value
+	case *object.Array:
+		values := make([]interface{}, len(o.Elements))
+		for i, elem := range o.Elements {
+			values[i] = FromObject(elem)
+		}
+		return values
+	case *object.Map:
+		values := make(map[interface{}]interface{}, o.Len())
+		for _, key := range o.Keys() {
+			values[FromObject(key)] = FromObject(o.Get(key))
+		}
+		return values
+	}
+	return obj
+}
+
+func toObjects(values []interface{}) ([]object.Object, error) {
+	objects := make([]object.Object, len(values))
+	for i, value := range values {
+		obj, err := ToObject(value)
+		if err != nil {
+			return nil, err
+		}
+		objects[i] = obj
+	}
+	return objects, nil
+}
@@ -0,0 +1,118 @@
+// Package eldr embeds the Eldr interpreter in Go programs,
+// converting values between Go and Eldr on the way in and out.
+package eldr
+
+import (
+	"context"
+	"fmt"
+	"github.com/Onelio/Eldrlang/evaluator"
+	"github.com/Onelio/Eldrlang/parser"
+	"github.com/Onelio/Eldrlang/util"
+	"io/ioutil"
+	"strings"
+)
+
+// Error holds the diagnostics of a failed parse or evaluation,
+// and the limit that stopped it if any.
+type Error struct {
+	Errors util.Errors
+	Limit  *evaluator.LimitError
+}
+
+func (e *Error) Error() string {
+	return strings.TrimSpace(e.Errors.String())
+}
+
+func (e *Error) Unwrap() error {
+	if e.Limit == nil {
+		return nil
+	}
+	return e.Limit
+}
+
+type Interpreter struct {
+	parser *parser.Parser
+	eval   *evaluator.Evaluator
+}
+
+func New() *Interpreter {
+	return &Interpreter{
+		parser: parser.NewParser(),
+		eval:   evaluator.NewEvaluator(),
+	}
+}
+
+// Evaluator exposes the underlying evaluator for advanced use.
+func (i *Interpreter) Evaluator() *evaluator.Evaluator {
+	return i.eval
+}
+
+// SetLimits bounds the resources of every later evaluation.
+func (i *Interpreter) SetLimits(limits evaluator.Limits) {
+	i.eval.Limits = limits
+}
+
+// RunString evaluates code and returns the value of its last
+// statement converted to Go.
+func (i *Interpreter) RunString(code string) (interface{}, error) {
+	return i.RunStringContext(context.Background(), code)
+}
+
+// RunStringContext is RunString stopping once ctx is done.
+func (i *Interpreter) RunStringContext(ctx context.Context, code string) (interface{}, error) {
+	parsed := i.parser.ParsePackage(code, "main")
+	if parsed.Errors.Len() > 0 {
+		return nil, &Error{Errors: parsed.Errors}
+	}
+	out := i.eval.EvaluateContext(ctx, parsed)
+	if out.Errors.Len() > 0 {
+		return nil, &Error{Errors: out.Errors, Limit: out.Limit}
+	}
+	return FromObject(out.Object), nil
+}
+
+func (i *Interpreter) RunFile(path string) (interface{}, error) {
+	code, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return i.RunString(string(code))
+}
+
+// Set binds a Go value to a global Eldr variable.
+func (i *Interpreter) Set(name string, value interface{}) error {
+	obj, err := ToObject(value)
+	if err != nil {
+		return err
+	}
+	i.eval.SetValue(name, obj)
+	return nil
+}
+
+// Get returns the Go value of a variable.
+func (i *Interpreter) Get(name string) (interface{}, error) {
+	obj := i.eval.GetValue(name)
+	if obj == nil {
+		return nil, fmt.Errorf("identifier %q not found", name)
+	}
+	return FromObject(obj), nil
+}
+
+// Call invokes an Eldr function with Go arguments.
+func (i *Interpreter) Call(fnName string, args ...interface{}) (interface{}, error) {
+	params, err := toObjects(args)
+	if err != nil {
+		return nil, err
+	}
+	out := i.eval.Call(fnName, params...)
+	if out.Errors.Len() > 0 {
+		return nil, &Error{Errors: out.Errors, Limit: out.Limit}
+	}
+	return FromObject(out.Object), nil
+}
+
+// Close stops the generators and closes the files left open
+// by the code run, once the interpreter is no longer needed.
+func (i *Interpreter) Close() {
+	i.eval.Close()
+}
@@ -0,0 +1,121 @@
+package eldr
+
+import (
+	"errors"
+	"github.com/Onelio/Eldrlang/evaluator"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInterpreter(t *testing.T) {
+	in := New()
+	if err := in.Set("name", "world"); err != nil {
+		t.Fatal(err)
+	}
+	if err := in.Set("count", 41); err != nil {
+		t.Fatal(err)
+	}
+	result, err := in.RunString(`
+var greeting = "hello " + name;
+fun inc(n) { return n + 1; }
+count = inc(count);
+count == 42;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != true {
+		t.Fatalf("expected true got %v", result)
+	}
+	if greeting, _ := in.Get("greeting"); greeting != "hello world" {
+		t.Fatalf("expected greeting got %v", greeting)
+	}
+	if count, _ := in.Get("count"); count != int64(42) {
+		t.Fatalf("expected 42 got %#v", count)
+	}
+	if sum, err := in.Call("inc", int64(9)); err != nil || sum != int64(10) {
+		t.Fatalf("expected 10 got %v (%v)", sum, err)
+	}
+	if size, err := in.Call("len", []string{"a", "b", "c"}); err != nil || size != int64(3) {
+		t.Fatalf("expected builtin len 3 got %v (%v)", size, err)
+	}
+	if _, err := in.Get("missing"); err == nil {
+		t.Fatal("expected error for missing variable")
+	}
+	if _, err := in.Call("inc"); err == nil {
+		t.Fatal("expected arity error")
+	}
+	if _, err := in.RunString("if a { }"); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestConversion(t *testing.T) {
+	values := []interface{}{
+		int64(1), "text", true, nil,
+		[]interface{}{int64(1), "two", []interface{}{false}},
+		map[interface{}]interface{}{"a": int64(1), int64(2): "b"},
+		new(big.Int).Lsh(big.NewInt(1), 100),
+	}
+	for _, value := range values {
+		obj, err := ToObject(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back := FromObject(obj); !reflect.DeepEqual(back, value) {
+			t.Fatalf("expected %#v got %#v", value, back)
+		}
+	}
+	if _, err := ToObject(3.5); err == nil {
+		t.Fatal("expected error for float")
+	}
+	if _, err := ToObject(map[float64]int{1.5: 1}); err == nil {
+		t.Fatal("expected error for float keys")
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eldr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "script.eld")
+	_ = ioutil.WriteFile(path, []byte(`fun double(x) { return x * 2; }`), 0644)
+
+	in := New()
+	if _, err := in.RunFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := in.Call("double", 21); err != nil || result != int64(42) {
+		t.Fatalf("expected 42 got %v (%v)", result, err)
+	}
+	// Files stay open across calls until the interpreter is closed
+	if _, err := in.RunString(`var f = fopen("` + path + `"); fun first() { return freadline(f); }`); err != nil {
+		t.Fatal(err)
+	}
+	if line, err := in.Call("first"); err != nil || line != "fun double(x) { return x * 2; }" {
+		t.Fatalf("expected first line got %v (%v)", line, err)
+	}
+	in.Close()
+	if _, err := in.Call("first"); err == nil {
+		t.Fatal("expected closed file error")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	in := New()
+	in.SetLimits(evaluator.Limits{Steps: 500})
+	_, err := in.RunString(`var n = 0; loop { n = n + 1; }`)
+	var limit *evaluator.LimitError
+	if !errors.As(err, &limit) || limit.Limit != evaluator.StepLimit {
+		t.Fatalf("expected step limit error got %v", err)
+	}
+	if result, err := in.RunString(`n > 0;`); err != nil || result != true {
+		t.Fatalf("expected interpreter to keep working got %v (%v)", result, err)
+	}
+}
@@ -0,0 +1,94 @@
+package evaluator
+
+import (
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+	"github.com/Onelio/Eldrlang/util"
+)
+
+// Names self and super are bound to while running a method,
+// which as keywords can't be shadowed by variables.
+const (
+	selfName  = "self"
+	superName = "super"
+)
+
+func (e *Evaluator) evalClass(c *parser.Class) {
+	var parent *object.Class
+	if c.Parent != nil {
+		stored := e.evalIdentifier(c.Parent)
+		if stored == nil {
+			return
+		}
+		var ok bool
+		if parent, ok = stored.(*object.Class); !ok {
+			err := util.NewError(c.Parent.Token, util.NotAClassType, c.Parent.Value)
+			e.errors.Add(err)
+			return
+		}
+	}
+	class := object.NewClass(c.Name.Value, parent)
+	for _, method := range c.Methods {
+		class.Methods[method.Name.Value] = &object.Function{
+			Name:       c.Name.Value + "." + method.Name.Value,
+			Parameters: method.Params,
+			Body:       method.Body,
+			Generator:  method.Generator,
+		}
+	}
+	e.defineConstant(c.Name, class, func(old object.Object) bool {
+		_, same := old.(*object.Class)
+		return same
+	})
+}
+
+// evalSuper binds to the running instance the method named as
+// found from the parent of the class declaring the running one.
+func (e *Evaluator) evalSuper(field *parser.Field) object.Object {
+	self, _ := e.GetValue(selfName).(*object.Instance)
+	parent, _ := e.GetValue(superName).(*object.Class)
+	if self == nil || parent == nil {
+		e.unknownField(field, superName)
+		return nil
+	}
+	method := parent.Lookup(field.Name.Value)
+	if method == nil {
+		e.unknownField(field, parent.Name)
+		return nil
+	}
+	return method.Bind(self)
+}
+
+// construct returns a new instance of a class, once its init
+// method, if any, has run with the arguments.
+func (e *Evaluator) construct(class *object.Class, params []object.Object, call lexer.Token) object.Object {
+	instance := object.NewInstance(class)
+	init := class.Lookup("init")
+	if init == nil {
+		if len(params) != 0 {
+			err := util.NewError(call, util.ExpectedFuncP, 0)
+			e.errors.Add(err)
+			return nil
+		}
+		return e.allocate(instance, call)
+	}
+	errors := e.errors.Len()
+	e.applyFunction(init.Bind(instance), params, call)
+	if e.errors.Len() > errors {
+		return nil
+	}
+	return e.allocate(instance, call)
+}
+
+// exeMethod runs a method with self and super bound in a
+// context around the one of its call.
+func (e *Evaluator) exeMethod(method *object.Method, params []object.Object, call lexer.Token) object.Object {
+	e.PushChild()
+	defer e.PopChild()
+	e.SetConstant(selfName, method.Self)
+	if method.Owner.Parent != nil {
+		e.SetConstant(superName, method.Owner.Parent)
+	}
+	return e.exeFuncCall(method.Fun, params, call)
+}
@@ -0,0 +1,68 @@
+package evaluator
+
+import (
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+	"github.com/Onelio/Eldrlang/util"
+)
+
+func (e *Evaluator) evalEnum(en *parser.Enum) {
+	enum := &object.Enum{Name: en.Name.Value}
+	for _, variant := range en.Variants {
+		var params []string
+		for _, param := range variant.Params {
+			params = append(params, param.Value)
+		}
+		enum.AddVariant(variant.Name.Value, params, variant.Payload)
+	}
+	// A redefined enum has values different from the old ones
+	e.defineConstant(en.Name, enum, func(old object.Object) bool {
+		_, same := old.(*object.Enum)
+		return same
+	})
+}
+
+func (e *Evaluator) evalVariant(enum *object.Enum, name *parser.Identifier) object.Object {
+	value := enum.Value(name.Value)
+	if value == nil {
+		err := util.NewError(name.Token, util.UnknownVarian, enum.Name, name.Value)
+		e.errors.Add(err)
+	}
+	return value
+}
+
+// matchVariant tells whether value is of the variant of a
+// pattern, matching its payload against the ones nested.
+func (e *Evaluator) matchVariant(pattern *parser.VariantPattern, value object.Object) bool {
+	stored := e.evalIdentifier(pattern.Enum)
+	if stored == nil {
+		return false
+	}
+	enum, ok := stored.(*object.Enum)
+	if !ok {
+		err := util.NewError(pattern.Enum.Token, util.NotAnEnumType, pattern.Enum.Value)
+		e.errors.Add(err)
+		return false
+	}
+	variant := enum.Variant(pattern.Name.Value)
+	if variant == nil {
+		err := util.NewError(pattern.Name.Token, util.UnknownVarian, enum.Name, pattern.Name.Value)
+		e.errors.Add(err)
+		return false
+	}
+	if variant.Payload != pattern.Payload || len(variant.Params) != len(pattern.Params) {
+		err := util.NewError(pattern.Token, util.ExpectedFuncP, len(variant.Params))
+		e.errors.Add(err)
+		return false
+	}
+	enumValue, ok := value.(*object.EnumValue)
+	if !ok || enumValue.Variant != variant {
+		return false
+	}
+	for i, param := range pattern.Params {
+		if !e.matchPattern(param, enumValue.Values[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,60 +1,172 @@
 package evaluator
 
 import (
+	"context"
+	"github.com/Onelio/Eldrlang/lexer"
 	"github.com/Onelio/Eldrlang/object"
 	"github.com/Onelio/Eldrlang/parser"
 	"github.com/Onelio/Eldrlang/util"
+	"math/big"
+)
+
+// flow tells enclosing blocks to stop evaluating their
+// statements after a break or a return.
+type flow int
+
+const (
+	flowNone flow = iota
+	flowBreak
+	flowContinue
+	flowReturn
 )
 
 type Evaluator struct {
 	*object.Runtime
+	Limits  Limits
 	errors  util.Errors
 	srcCode *parser.Package
+	frames  []Frame
+	hook    Hook
+	flow    flow
+	target  string
+	tail    *tailCall
+	usage   usage
+	// Generator running, and the ones started by the evaluation
+	yielder    *generator
+	generators []*generator
+}
+
+// tailCall is a call in tail position left for the function
+// returning it to run, in place of its own frame.
+type tailCall struct {
+	fun     *object.Function
+	params  []object.Object
+	call    lexer.Token
+	context *object.Context
 }
 
 func NewEvaluator() *Evaluator {
-	return &Evaluator{Runtime: object.NewRuntime()}
+	return &Evaluator{
+		Runtime: object.NewRuntime(),
+		Limits:  Limits{Depth: DefaultDepth},
+	}
+}
+
+// Close stops the generators left suspended and closes the
+// files still open, once the evaluator is no longer needed.
+func (e *Evaluator) Close() {
+	e.stopGenerators()
+	e.Runtime.Close()
 }
 
 func (e *Evaluator) Evaluate(src *parser.Package) *Output {
+	return e.EvaluateContext(context.Background(), src)
+}
+
+// EvaluateContext evaluates a package until it ends, a limit
+// is exceeded or ctx is done.
+func (e *Evaluator) EvaluateContext(ctx context.Context, src *parser.Package) *Output {
 	e.srcCode = src
-	var result = Output{}
-	for _, node := range e.srcCode.Nodes {
-		result.Object = e.EvaluateNode(node)
-	}
-	result.Errors = e.errors
-	e.errors.Clear()
-	return &result
+	return e.run(ctx, func() (result object.Object) {
+		for _, node := range e.srcCode.Nodes {
+			result = e.evalStatement(node)
+			e.flow = flowNone
+		}
+		return result
+	})
+}
+
+// Call invokes the function bound to name with already
+// evaluated arguments, as if it was called from Eldr code.
+func (e *Evaluator) Call(name string, args ...object.Object) *Output {
+	return e.CallContext(context.Background(), name, args...)
+}
+
+func (e *Evaluator) CallContext(ctx context.Context, name string, args ...object.Object) *Output {
+	ident := &parser.Identifier{Token: lexer.Token{Type: lexer.IDENT, Literal: name}, Value: name}
+	return e.run(ctx, func() object.Object {
+		if fun := e.evalIdentifier(ident); fun != nil {
+			return e.applyFunction(fun, args, ident.Token)
+		}
+		return nil
+	})
 }
 
 func (e *Evaluator) EvaluateNode(node parser.Node) object.Object {
+	e.step(node)
 	switch stat := node.(type) {
 	case *parser.Boolean:
-		return &object.Boolean{Value: stat.Value}
+		return e.allocate(&object.Boolean{Value: stat.Value}, stat.Token)
 	case *parser.Integer:
-		return &object.Integer{Value: stat.Value}
+		if stat.Big != nil {
+			return e.allocate(&object.BigInt{Value: stat.Big}, stat.Token)
+		}
+		return e.allocate(&object.Integer{Value: stat.Value}, stat.Token)
 	case *parser.String:
-		return &object.String{Value: stat.Value}
+		return e.allocate(&object.String{Value: stat.Value}, stat.Token)
 	case *parser.Identifier:
 		return e.evalIdentifier(stat)
 	case *parser.Variable:
-		e.SetValue(stat.Literal(), &object.Null{})
+		if e.declare(stat.Name) {
+			e.SetValue(stat.Literal(), &object.Null{})
+		}
 	case *parser.Assign:
 		e.evalAssign(stat)
+	case *parser.Update:
+		e.evalUpdate(stat)
 	case *parser.Prefix:
-		return e.evalPrefix(stat)
+		return e.allocate(e.evalPrefix(stat), stat.Token)
 	case *parser.Infix:
-		return e.evalInfix(stat)
+		return e.allocate(e.evalInfix(stat), stat.Token)
 	case *parser.Block:
 		return e.evalBlock(stat)
 	case *parser.Conditional:
 		return e.evalConditional(stat)
+	case *parser.Loop:
+		return e.evalLoop(stat, "")
+	case *parser.While:
+		return e.evalWhile(stat, "")
+	case *parser.For:
+		return e.evalFor(stat, "")
+	case *parser.ForIn:
+		return e.evalForIn(stat, "")
+	case *parser.Labeled:
+		return e.evalLabeled(stat)
+	case *parser.Match:
+		return e.evalMatch(stat)
+	case *parser.Struct:
+		e.evalStruct(stat)
+	case *parser.StructLiteral:
+		return e.evalStructLiteral(stat)
+	case *parser.Field:
+		return e.evalField(stat)
+	case *parser.Class:
+		e.evalClass(stat)
+	case *parser.Enum:
+		e.evalEnum(stat)
+	case *parser.Self, *parser.Super:
+		return e.GetValue(selfName)
 	case *parser.Function:
 		e.evalFunction(stat)
 	case *parser.FuncCall:
 		return e.evalFuncCall(stat)
+	case *parser.Yield:
+		e.evalYield(stat)
 	case *parser.Return:
-		return e.EvaluateNode(stat.Exp)
+		if call, ok := stat.Exp.(*parser.FuncCall); ok && len(e.frames) > 0 {
+			return e.evalTailCall(call)
+		}
+		result := e.EvaluateNode(stat.Exp)
+		e.flow = flowReturn
+		return result
+	case *parser.Break:
+		e.flow, e.target = flowBreak, ""
+		if stat.Continue() {
+			e.flow = flowContinue
+		}
+		if stat.Label != nil {
+			e.target = stat.Label.Value
+		}
 	}
 	return nil
 }
@@ -63,7 +175,7 @@ func (e *Evaluator) evalIdentifier(ident *parser.Identifier) object.Object {
 	if val := e.GetValue(ident.Value); val != nil {
 		return val
 	}
-	if builtin, ok := object.Builtins[ident.Value]; ok {
+	if builtin := e.Builtins.Get(ident.Value); builtin != nil {
 		return builtin
 	}
 	err := util.NewError(ident.Token, util.IdentNotFound, ident.Value)
@@ -72,10 +184,87 @@ func (e *Evaluator) evalIdentifier(ident *parser.Identifier) object.Object {
 }
 
 func (e *Evaluator) evalAssign(stat *parser.Assign) {
+	switch left := stat.Left.(type) {
+	case *parser.Constant:
+		if e.declare(left.Name) {
+			e.SetConstant(left.Literal(), e.EvaluateNode(stat.Right))
+		}
+		return
+	case *parser.Field:
+		if obj := e.fieldOwner(left); obj != nil && !obj.Set(left.Name.Value, e.EvaluateNode(stat.Right)) {
+			e.unknownField(left, obj.Name())
+		}
+		return
+	case *parser.Identifier:
+		if e.IsConstant(left.Value) {
+			err := util.NewError(left.Token, util.AssignToConst, left.Value)
+			e.errors.Add(err)
+			return
+		}
+	}
 	e.EvaluateNode(stat.Left)
 	name := stat.Left.Literal()
-	if e.GetValue(name) != nil {
-		e.SetValue(name, e.EvaluateNode(stat.Right))
+	if e.GetValue(name) != nil && !e.IsConstant(name) {
+		e.Assign(name, e.EvaluateNode(stat.Right))
+	}
+}
+
+// evalUpdate evaluates the target once, operating on its
+// value before the one of the right side.
+func (e *Evaluator) evalUpdate(stat *parser.Update) {
+	var (
+		current object.Object
+		store   func(object.Object)
+	)
+	switch target := stat.Target.(type) {
+	case *parser.Identifier:
+		if e.IsConstant(target.Value) {
+			err := util.NewError(target.Token, util.AssignToConst, target.Value)
+			e.errors.Add(err)
+			return
+		}
+		current = e.evalIdentifier(target)
+		store = func(value object.Object) { e.Assign(target.Value, value) }
+	case *parser.Field:
+		obj := e.fieldOwner(target)
+		if obj == nil {
+			return
+		}
+		if current = obj.Get(target.Name.Value); current == nil {
+			e.unknownField(target, obj.Name())
+		}
+		store = func(value object.Object) { obj.Set(target.Name.Value, value) }
+	}
+	if current == nil {
+		return
+	}
+	var value object.Object = &object.Integer{Value: 1}
+	if stat.Value != nil {
+		value = e.EvaluateNode(stat.Value)
+	}
+	result := e.allocate(e.operate(stat.Token, stat.Operator, current, value), stat.Token)
+	if result != nil {
+		store(result)
+	}
+}
+
+// declare checks a name can be bound in the current context,
+// which it can't when a constant already is.
+func (e *Evaluator) declare(ident *parser.Identifier) bool {
+	if e.Context().IsConstant(ident.Value) {
+		err := util.NewError(ident.Token, util.DuplicateDecl, ident.Value)
+		e.errors.Add(err)
+		return false
+	}
+	return true
+}
+
+// defineConstant binds the definitions of functions and types,
+// which are constants that may only be redefined by others of
+// the same kind, as the console does.
+func (e *Evaluator) defineConstant(name *parser.Identifier, obj object.Object, same func(object.Object) bool) {
+	if same(e.Context().Get(name.Value)) || e.declare(name) {
+		e.SetConstant(name.Literal(), obj)
 	}
 }
 
@@ -88,12 +277,13 @@ func (e *Evaluator) evalPrefix(pref *parser.Prefix) object.Object {
 			return nil
 		}
 		return &object.Boolean{Value: !exp.Value}
-	case *object.Integer:
+	case *object.Integer, *object.BigInt:
+		value, _ := object.ToBig(exp)
 		switch pref.Operator {
 		case "+":
-			return &object.Integer{Value: exp.Value}
+			return object.FromBig(value)
 		case "-":
-			return &object.Integer{Value: -exp.Value}
+			return object.FromBig(new(big.Int).Neg(value))
 		default:
 			err := util.NewError(pref.Token, util.InvalidOpForO)
 			e.errors.Add(err)
@@ -106,67 +296,56 @@ func (e *Evaluator) evalPrefix(pref *parser.Prefix) object.Object {
 }
 
 func (e *Evaluator) evalInfix(inf *parser.Infix) object.Object {
+	left := e.EvaluateNode(inf.Left)
 	right := e.EvaluateNode(inf.Right)
-	switch left := e.EvaluateNode(inf.Left).(type) {
+	return e.operate(inf.Token, inf.Operator, left, right)
+}
+
+func (e *Evaluator) operate(token lexer.Token, operator string, left, right object.Object) object.Object {
+	switch left := left.(type) {
 	case *object.String:
 		str, valid := right.(*object.String)
 		if !valid {
-			err := util.NewError(inf.Token, util.InvalidOpComb)
+			err := util.NewError(token, util.InvalidOpComb)
 			e.errors.Add(err)
 			return nil
 		}
-		switch inf.Operator {
+		switch operator {
 		case "+":
 			return &object.String{
 				Value: left.Value + str.Value}
 		default:
-			err := util.NewError(inf.Token, util.InvalidOpForO)
+			err := util.NewError(token, util.InvalidOpForO)
 			e.errors.Add(err)
 			return nil
 		}
-	case *object.Integer:
-		num, valid := right.(*object.Integer)
+	case *object.Integer, *object.BigInt:
+		return e.evalIntegerInfix(token, operator, left, right)
+	case *object.Boolean:
+		val, valid := right.(*object.Boolean)
 		if !valid {
-			err := util.NewError(inf.Token, util.InvalidOpComb)
+			err := util.NewError(token, util.InvalidOpComb)
 			e.errors.Add(err)
 			return nil
 		}
-		switch inf.Operator {
-		case "+":
-			return &object.Integer{Value: left.Value + num.Value}
-		case "-":
-			return &object.Integer{Value: left.Value - num.Value}
-		case "*":
-			return &object.Integer{Value: left.Value * num.Value}
-		case "/":
-			return &object.Integer{Value: left.Value / num.Value}
-		case "<":
-			return &object.Boolean{Value: left.Value < num.Value}
-		case ">":
-			return &object.Boolean{Value: left.Value > num.Value}
+		switch operator {
 		case "==":
-			return &object.Boolean{Value: left.Value == num.Value}
+			return &object.Boolean{Value: left.Value == val.Value}
 		case "!=":
-			return &object.Boolean{Value: left.Value != num.Value}
+			return &object.Boolean{Value: left.Value != val.Value}
 		default:
-			err := util.NewError(inf.Token, util.InvalidOpForO)
+			err := util.NewError(token, util.InvalidOpForO)
 			e.errors.Add(err)
 			return nil
 		}
-	case *object.Boolean:
-		val, valid := right.(*object.Boolean)
-		if !valid {
-			err := util.NewError(inf.Token, util.InvalidOpComb)
-			e.errors.Add(err)
-			return nil
-		}
-		switch inf.Operator {
+	case *object.Struct, *object.Instance, *object.EnumValue:
+		switch operator {
 		case "==":
-			return &object.Boolean{Value: left.Value == val.Value}
+			return &object.Boolean{Value: object.Equals(left, right)}
 		case "!=":
-			return &object.Boolean{Value: left.Value != val.Value}
+			return &object.Boolean{Value: !object.Equals(left, right)}
 		default:
-			err := util.NewError(inf.Token, util.InvalidOpForO)
+			err := util.NewError(token, util.InvalidOpForO)
 			e.errors.Add(err)
 			return nil
 		}
@@ -176,19 +355,14 @@ func (e *Evaluator) evalInfix(inf *parser.Infix) object.Object {
 
 func (e *Evaluator) evalBlock(block *parser.Block) object.Object {
 	e.PushChild()
+	defer e.PopChild()
 	var result object.Object
 	for _, statement := range block.Nodes {
-		result = e.EvaluateNode(statement)
-		if result != nil {
-			if _, ok := statement.(*parser.Return); ok {
-				return result
-			}
-			if _, ok := statement.(*parser.Break); ok {
-				return nil
-			}
+		result = e.evalStatement(statement)
+		if e.flow != flowNone {
+			return result
 		}
 	}
-	e.PopChild()
 	return result
 }
 
@@ -200,63 +374,278 @@ func (e *Evaluator) evalConditional(ie *parser.Conditional) object.Object {
 		e.errors.Add(err)
 		return nil
 	}
+	var result object.Object
 	if cond.Value {
-		return e.EvaluateNode(ie.To)
+		result = e.EvaluateNode(ie.To)
 	} else if ie.Else != nil {
-		return e.EvaluateNode(ie.Else)
-	} else {
+		result = e.EvaluateNode(ie.Else)
+	}
+	// As an expression it's null when no block is chosen or the
+	// one chosen ends in a statement
+	if result == nil && ie.Valued && e.flow == flowNone {
+		return &object.Null{}
+	}
+	return result
+}
+
+func (e *Evaluator) evalLabeled(labeled *parser.Labeled) object.Object {
+	label := labeled.Label.Value
+	switch loop := labeled.Loop.(type) {
+	case *parser.Loop:
+		return e.evalLoop(loop, label)
+	case *parser.While:
+		return e.evalWhile(loop, label)
+	case *parser.For:
+		return e.evalFor(loop, label)
+	case *parser.ForIn:
+		return e.evalForIn(loop, label)
+	}
+	return nil
+}
+
+func (e *Evaluator) evalLoop(loop *parser.Loop, label string) object.Object {
+	return e.repeat(loop, label, nil, loop.Body, nil)
+}
+
+func (e *Evaluator) evalWhile(while *parser.While, label string) object.Object {
+	return e.repeat(while, label, e.condition(while, while.Require), while.Body, nil)
+}
+
+// evalFor declares the variables of Init in a context of the
+// loop, which every iteration gets a fresh child of.
+func (e *Evaluator) evalFor(loop *parser.For, label string) object.Object {
+	e.PushChild()
+	defer e.PopChild()
+	if loop.Init != nil {
+		errors := e.errors.Len()
+		e.evalStatement(loop.Init)
+		if e.errors.Len() > errors {
+			return nil
+		}
+	}
+	return e.repeat(loop, label, e.condition(loop, loop.Require), loop.Body, loop.Post)
+}
+
+// evalForIn binds the elements in a context of the loop, as
+// evalFor does with the variables of Init.
+func (e *Evaluator) evalForIn(loop *parser.ForIn, label string) object.Object {
+	value := e.EvaluateNode(loop.Iterable)
+	iterable, valid := value.(object.Iterable)
+	if !valid {
+		if value != nil {
+			err := util.NewError(loop.Token, util.NotIterableOb, value.Type())
+			e.errors.Add(err)
+		}
 		return nil
 	}
+	e.PushChild()
+	defer e.PopChild()
+	iterator := iterable.Iterator()
+	next := func() bool {
+		key, value, ok := iterator.Next()
+		if ok {
+			if loop.Key != nil {
+				e.SetValue(loop.Key.Value, e.allocate(key, loop.Token))
+			}
+			e.SetValue(loop.Value.Value, e.allocate(value, loop.Token))
+		}
+		return ok
+	}
+	return e.repeat(loop, label, next, loop.Body, nil)
+}
+
+// condition returns a check of require for repeat, nil when
+// there is none.
+func (e *Evaluator) condition(loop parser.Node, require parser.Expression) func() bool {
+	if require == nil {
+		return nil
+	}
+	return func() bool {
+		cond, valid := e.EvaluateNode(require).(*object.Boolean)
+		if !valid {
+			err := util.NewError(parser.TokenOf(loop), util.ExpectedCondV)
+			e.errors.Add(err)
+			return false
+		}
+		return cond.Value
+	}
+}
+
+// repeat evaluates the body, in a fresh context each time, and
+// then post while next holds, until a break or a return. It
+// also stops when failing, as errors would otherwise pile up
+// forever. Breaks and continues targeting an outer loop are
+// left for it to handle.
+func (e *Evaluator) repeat(loop parser.Node, label string, next func() bool, body *parser.Block, post parser.Expression) object.Object {
+	errors := e.errors.Len()
+	for {
+		e.step(loop)
+		if next != nil && !next() {
+			return nil
+		}
+		result := e.evalBlock(body)
+		targeted := e.target == "" || e.target == label
+		switch {
+		case e.flow == flowBreak && targeted:
+			e.flow, e.target = flowNone, ""
+			return nil
+		case e.flow == flowContinue && targeted:
+			e.flow, e.target = flowNone, ""
+		case e.flow != flowNone:
+			return result
+		}
+		if e.errors.Len() > errors {
+			return nil
+		}
+		if post != nil {
+			e.EvaluateNode(post)
+		}
+	}
 }
 
 func (e *Evaluator) evalFunction(f *parser.Function) {
-	fun := &object.Function{Parameters: f.Params, Body: f.Body}
-	e.SetValue(f.Name.Literal(), fun)
+	fun := &object.Function{Name: f.Name.Value, Parameters: f.Params, Body: f.Body, Generator: f.Generator}
+	e.defineConstant(f.Name, fun, func(old object.Object) bool {
+		_, same := old.(*object.Function)
+		return same
+	})
 }
 
 func (e *Evaluator) evalFuncCall(fc *parser.FuncCall) object.Object {
+	storedFun, params, call := e.evalCallee(fc)
+	if storedFun == nil {
+		return nil
+	}
+	return e.applyFunction(storedFun, params, call)
+}
+
+// evalTailCall returns a call to a function as a tail call
+// for the trampoline in runFunction to run.
+func (e *Evaluator) evalTailCall(fc *parser.FuncCall) object.Object {
+	defer func() { e.flow = flowReturn }()
+	storedFun, params, call := e.evalCallee(fc)
+	if fun, ok := storedFun.(*object.Function); ok && len(fun.Parameters) == len(params) {
+		e.tail = &tailCall{fun: fun, params: params, call: call, context: e.Context()}
+		return nil
+	}
+	if storedFun == nil {
+		return nil
+	}
+	return e.applyFunction(storedFun, params, call)
+}
+
+func (e *Evaluator) evalCallee(fc *parser.FuncCall) (object.Object, []object.Object, lexer.Token) {
+	if field, ok := fc.Function.(*parser.Field); ok {
+		storedFun := e.evalField(field)
+		if storedFun == nil {
+			return nil, nil, field.Name.Token
+		}
+		return storedFun, e.evalArguments(fc), field.Name.Token
+	}
 	ident := fc.Function.(*parser.Identifier)
 	storedFun := e.EvaluateNode(ident)
 	if storedFun == nil {
 		err := util.NewError(ident.Token, util.IdentNotFound, ident.Value)
 		e.errors.Add(err)
-		return nil
+		return nil, nil, ident.Token
 	}
+	return storedFun, e.evalArguments(fc), ident.Token
+}
+
+func (e *Evaluator) evalArguments(fc *parser.FuncCall) []object.Object {
 	var params []object.Object
 	for _, a := range fc.Arguments {
 		evaluated := e.EvaluateNode(a)
 		params = append(params, evaluated)
 	}
+	return params
+}
+
+func (e *Evaluator) applyFunction(storedFun object.Object, params []object.Object, call lexer.Token) object.Object {
 	switch fun := storedFun.(type) {
 	case *object.Function:
 		if len(fun.Parameters) != len(params) {
-			err := util.NewError(ident.Token, util.ExpectedFuncP, len(fun.Parameters))
+			err := util.NewError(call, util.ExpectedFuncP, len(fun.Parameters))
 			e.errors.Add(err)
 			return nil
 		}
-		return e.exeFuncCall(fun, params)
-	case *object.Builtin:
-		if fun.Size > -1 && fun.Size != len(params) {
-			err := util.NewError(ident.Token, util.ExpectedFuncP, fun.Size)
+		return e.exeFuncCall(fun, params, call)
+	case *object.Method:
+		if len(fun.Fun.Parameters) != len(params) {
+			err := util.NewError(call, util.ExpectedFuncP, len(fun.Fun.Parameters))
 			e.errors.Add(err)
 			return nil
 		}
-		return fun.Fun(params...)
+		return e.exeMethod(fun, params, call)
+	case *object.Class:
+		return e.construct(fun, params, call)
+	case *object.Variant:
+		if len(fun.Params) != len(params) {
+			err := util.NewError(call, util.ExpectedFuncP, len(fun.Params))
+			e.errors.Add(err)
+			return nil
+		}
+		return e.allocate(fun.New(params), call)
+	case *object.Builtin:
+		if err := fun.Check(params); err != nil {
+			e.errors.Add(util.NewError(call, "%s", err))
+			return nil
+		}
+		result, err := fun.Fun(e.Runtime, params...)
+		if err != nil {
+			e.errors.Add(util.NewError(call, util.BuiltinFailed, fun.Name, err))
+			return nil
+		}
+		return e.allocate(result, call)
 	default:
-		err := util.NewError(ident.Token, util.IdentNotAFunc, ident.Literal())
+		err := util.NewError(call, util.IdentNotAFunc, call.Literal)
 		e.errors.Add(err)
 		return nil
 	}
 }
 
-func (e *Evaluator) exeFuncCall(fun *object.Function, params []object.Object) object.Object {
-	e.PushChild()
-	for index, param := range fun.Parameters {
-		e.SetValue(param.Literal(), params[index])
+// exeFuncCall runs a function, or returns a generator that
+// will when the function is one.
+func (e *Evaluator) exeFuncCall(fun *object.Function, params []object.Object, call lexer.Token) object.Object {
+	if fun.Generator {
+		return e.newGenerator(fun, params, call)
+	}
+	return e.runFunction(fun, params, call)
+}
+
+// runFunction runs a function and then, as a trampoline, the
+// calls it returns in tail position. These replace the frame
+// of their caller instead of nesting in it, so tail recursion
+// runs in constant Go stack. The contexts of the caller are
+// flattened into one for them to still see its names.
+func (e *Evaluator) runFunction(fun *object.Function, params []object.Object, call lexer.Token) object.Object {
+	if e.Limits.Depth > 0 && len(e.frames) >= e.Limits.Depth {
+		e.stop(DepthLimit, call, nil)
+	}
+	base := e.Context()
+	defer e.Switch(base)
+	for {
+		e.PushChild()
+		for index, param := range fun.Parameters {
+			e.SetValue(param.Literal(), params[index])
+		}
+		e.frames = append(e.frames, Frame{Name: fun.Name, Call: call, Context: e.Context()})
+		result := e.EvaluateNode(fun.Body)
+		e.flow = flowNone
+		e.frames = e.frames[:len(e.frames)-1]
+		e.PopChild()
+		tail := e.tail
+		if tail == nil {
+			return result
+		}
+		e.tail = nil
+		fun, params, call = tail.fun, tail.params, tail.call
+		e.Switch(tail.context)
+		e.Flatten(base)
+		if fun.Generator {
+			return e.newGenerator(fun, params, call)
+		}
 	}
-	result := e.EvaluateNode(fun.Body)
-	e.PopChild()
-	return result
 }
 
 func (e *Evaluator) exeBuiltin(fun *object.Function, params []object.Object) object.Object {
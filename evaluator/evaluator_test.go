@@ -1,9 +1,17 @@
 package evaluator
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"github.com/Onelio/Eldrlang/object"
 	"github.com/Onelio/Eldrlang/parser"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestEvaluatorNodes(t *testing.T) {
@@ -96,3 +104,592 @@ hello("world");
 		t.Log("RUN-FAIL")
 	}
 }
+
+func TestBuiltins(t *testing.T) {
+	var (
+		p     = parser.NewParser()
+		eval  = NewEvaluator()
+		other = NewEvaluator()
+	)
+	err := eval.Builtins.Register(&object.Builtin{
+		Name: "repeat",
+		Params: []object.Param{
+			{Name: "text", Types: []object.Type{object.STRING}},
+			{Name: "times", Types: []object.Type{object.INTEGER}, Optional: true},
+		},
+		Doc: "Repeats a string.",
+		Fun: func(rt *object.Runtime, args ...object.Object) (object.Object, error) {
+			times := int64(2)
+			if len(args) > 1 {
+				times = args[1].(*object.Integer).Value
+			}
+			return &object.String{Value: strings.Repeat(args[0].(*object.String).Value, int(times))}, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		code     string
+		expected string
+		err      string
+	}{
+		{`repeat("ab");`, "abab", ""},
+		{`repeat("ab", 3);`, "ababab", ""},
+		{`repeat(1);`, "", `argument 1 of "repeat" expected string but got integer`},
+		{`repeat("a", 1, 2);`, "", `"repeat" expects 1 to 2 arguments but got 3`},
+		{`len(true);`, "", `argument 1 of "len" expected string, array or map but got boolean`},
+		{`len();`, "", `"len" expects 1 arguments but got 0`},
+	}
+	for _, test := range tests {
+		out := eval.Evaluate(p.ParsePackage(test.code, "main"))
+		if test.err != "" {
+			if out.Errors.Len() != 1 || out.Errors[0].Message() != test.err {
+				t.Fatalf("%s expected error %q got %q", test.code, test.err, out.Errors.String())
+			}
+			continue
+		}
+		if out.Errors.Len() > 0 || out.Object.Inspect() != test.expected {
+			t.Fatalf("%s expected %q got %v %s", test.code, test.expected, out.Object, out.Errors.String())
+		}
+	}
+	// Registries are not shared between evaluators
+	if out := other.Evaluate(p.ParsePackage(`repeat("ab");`, "main")); out.Errors.Len() == 0 {
+		t.Fatal("builtin leaked to another evaluator")
+	}
+	eval.Builtins.Remove("repeat")
+	if out := eval.Evaluate(p.ParsePackage(`repeat("ab");`, "main")); out.Errors.Len() == 0 {
+		t.Fatal("removed builtin still callable")
+	}
+	err = eval.Builtins.Register(&object.Builtin{
+		Name:   "bad",
+		Params: []object.Param{{Name: "rest", Variadic: true}, {Name: "last"}},
+		Fun:    func(rt *object.Runtime, args ...object.Object) (object.Object, error) { return nil, nil },
+	})
+	if err == nil {
+		t.Fatal("expected error registering a non trailing variadic parameter")
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var (
+		data    = filepath.Join(dir, "data")
+		allowed = filepath.Join(data, "allowed.txt")
+		secret  = filepath.Join(dir, "secret.txt")
+		link    = filepath.Join(data, "link.txt")
+	)
+	_ = os.Mkdir(data, 0755)
+	_ = ioutil.WriteFile(allowed, []byte("public"), 0644)
+	_ = ioutil.WriteFile(secret, []byte("secret"), 0644)
+	_ = os.Symlink(secret, link)
+
+	p := parser.NewParser()
+	eval := NewEvaluator()
+	eval.Policy = &object.Policy{Read: []string{data}}
+	var tests = []struct {
+		code     string
+		expected string
+		err      string
+	}{
+		{fmt.Sprintf(`fread(fopen("%s"));`, allowed), "public", ""},
+		{fmt.Sprintf(`fopen("%s");`, secret), "",
+			fmt.Sprintf(`"fopen" failed: permission denied: read access to "%s"`, secret)},
+		{fmt.Sprintf(`fopen("%s");`, link), "",
+			fmt.Sprintf(`"fopen" failed: permission denied: read access to "%s"`, link)},
+		{fmt.Sprintf(`fopen("%s/../secret.txt");`, data), "",
+			fmt.Sprintf(`"fopen" failed: permission denied: read access to "%s/../secret.txt"`, data)},
+		{fmt.Sprintf(`fopen("%s", "a");`, allowed), "",
+			fmt.Sprintf(`"fopen" failed: permission denied: write access to "%s"`, allowed)},
+		{fmt.Sprintf(`fremove("%s");`, allowed), "",
+			fmt.Sprintf(`"fremove" failed: permission denied: write access to "%s"`, allowed)},
+		{`print("x");`, "", `"print" failed: permission denied: write access to "stdout"`},
+		{`scan("x");`, "", `"scan" failed: permission denied: read access to "stdin"`},
+	}
+	for _, test := range tests {
+		out := eval.Evaluate(p.ParsePackage(test.code, "main"))
+		if test.err != "" {
+			if out.Errors.Len() != 1 || out.Errors[0].Message() != test.err {
+				t.Fatalf("%s expected error %q got %q", test.code, test.err, out.Errors.String())
+			}
+			continue
+		}
+		if out.Errors.Len() > 0 || out.Object.Inspect() != test.expected {
+			t.Fatalf("%s expected %q got %v %s", test.code, test.expected, out.Object, out.Errors.String())
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "notes.txt")
+
+	p := parser.NewParser()
+	eval := NewEvaluator()
+	var tests = []struct {
+		code     string
+		expected string
+		err      string
+	}{
+		{fmt.Sprintf(`fexists("%s");`, path), "false", ""},
+		{fmt.Sprintf(`fopen("%s");`, path), "", "no such file or directory"},
+		{fmt.Sprintf(`var f = fopen("%s", "w"); fwrite(f, "one
+two
+"); fclose(f); fexists("%s");`, path, path), "true", ""},
+		{fmt.Sprintf(`var f = fopen("%s", "a"); fwrite(f, "three");`, path), "5", ""},
+		{fmt.Sprintf(`var f = fopen("%s"); freadline(f) + freadline(f);`, path), "one\ntwo\n", ""},
+		{fmt.Sprintf(`var f = fopen("%s"); freadline(f); fread(f);`, path), "two\nthree", ""},
+		{fmt.Sprintf(`var f = fopen("%s"); fread(f); freadline(f);`, path), "", ""},
+		{fmt.Sprintf(`var f = fopen("%s"); freadline(f); fseek(f, 0); fread(f);`, path), "one\ntwo\nthree", ""},
+		{fmt.Sprintf(`var f = fopen("%s", "rw"); freadline(f); fwrite(f, "TWO"); fseek(f, -9, 2); fread(f);`, path), "TWO\nthree", ""},
+		{fmt.Sprintf(`var f = fopen("%s"); fseek(f, 0, 99999999999999999999);`, path), "", "out of range"},
+		{fmt.Sprintf(`var f = fopen("%s"); fwrite(f, "x");`, path), "", "not open for writing"},
+		{fmt.Sprintf(`var f = fopen("%s", "w"); fread(f);`, path), "", "not open for reading"},
+		{fmt.Sprintf(`var f = fopen("%s"); fclose(f); fread(f);`, path), "", "file already closed"},
+		{fmt.Sprintf(`fopen("%s", "x");`, path), "", "invalid file mode"},
+		{fmt.Sprintf(`fremove("%s"); fexists("%s");`, path, path), "false", ""},
+	}
+	for _, test := range tests {
+		out := eval.Evaluate(p.ParsePackage(test.code, "main"))
+		if test.err != "" {
+			if out.Errors.Len() != 1 || !strings.Contains(out.Errors[0].Message(), test.err) {
+				t.Fatalf("%s expected error %q got %q", test.code, test.err, out.Errors.String())
+			}
+			continue
+		}
+		if out.Errors.Len() > 0 || out.Object.Inspect() != test.expected {
+			t.Fatalf("%s expected %q got %v %s", test.code, test.expected, out.Object, out.Errors.String())
+		}
+	}
+	// Files stay open across evaluations until the runtime is closed
+	out := eval.Evaluate(p.ParsePackage(fmt.Sprintf(`var f = fopen("%s", "w");`, path), "main"))
+	file, _ := eval.GetValue("f").(*object.File)
+	if out.Errors.Len() > 0 || file == nil || file.Closed() {
+		t.Fatalf("file closed early %v %s", file, out.Errors.String())
+	}
+	if out := eval.Evaluate(p.ParsePackage(`fwrite(f, "x");`, "main")); out.Errors.Len() > 0 {
+		t.Fatalf("write after evaluation failed %s", out.Errors.String())
+	}
+	eval.Close()
+	if !file.Closed() {
+		t.Fatalf("file left open %v", file)
+	}
+}
+
+func TestStreams(t *testing.T) {
+	var (
+		p     = parser.NewParser()
+		evals = []*Evaluator{NewEvaluator(), NewEvaluator()}
+		outs  = []*bytes.Buffer{{}, {}}
+		errs  = []*bytes.Buffer{{}, {}}
+	)
+	for i, eval := range evals {
+		eval.Stdin = strings.NewReader(fmt.Sprintf("eval %d\n%d\n", i, i*10))
+		eval.Stdout, eval.Stderr = outs[i], errs[i]
+	}
+	code := `
+var name = input("name? ");
+var n = 0;
+scan(n);
+println("hello ", name, " ", n + 1, " ", true);
+eprint("done");
+input();
+`
+	for i, eval := range evals {
+		out := eval.Evaluate(p.ParsePackage(code, "main"))
+		if out.Errors.Len() != 1 || out.Errors[0].Message() != `"input" failed: EOF` {
+			t.Fatalf("expected EOF error got %q", out.Errors.String())
+		}
+		expected := fmt.Sprintf("name? hello eval %d %d true\n", i, i*10+1)
+		if outs[i].String() != expected || errs[i].String() != "done" {
+			t.Fatalf("expected %q and %q got %q and %q", expected, "done", outs[i], errs[i])
+		}
+	}
+}
+
+func TestBigIntegers(t *testing.T) {
+	var tests = []struct {
+		code     string
+		expected string
+	}{
+		{`9223372036854775807 + 1;`, "9223372036854775808"},
+		{`-9223372036854775807 - 2;`, "-9223372036854775809"},
+		{`-(-9223372036854775807 - 1);`, "9223372036854775808"},
+		{`4294967296 * 4294967296;`, "18446744073709551616"},
+		{`-1 * (-9223372036854775807 - 1);`, "9223372036854775808"},
+		{`(-9223372036854775807 - 1) / -1;`, "9223372036854775808"},
+		{`123456789012345678901234567890 - 123456789012345678901234567890 + 1;`, "1"},
+		{`99999999999999999999 / 3;`, "33333333333333333333"},
+		{`99999999999999999999 > 1;`, "true"},
+		{`1 < 99999999999999999999;`, "true"},
+		{`99999999999999999999 == 99999999999999999999;`, "true"},
+		{`99999999999999999999 != 99999999999999999998;`, "true"},
+		{`fun fact(n) { if (n < 2) { return 1; } return n * fact(n - 1); } fact(25);`,
+			"15511210043330985984000000"},
+	}
+	p := parser.NewParser()
+	for _, test := range tests {
+		out := NewEvaluator().Evaluate(p.ParsePackage(test.code, "main"))
+		if out.Errors.Len() > 0 || out.Object == nil || out.Object.Inspect() != test.expected {
+			t.Fatalf("%s expected %s got %v %s", test.code, test.expected, out.Object, out.Errors.String())
+		}
+	}
+	// Results back in range are plain integers again
+	out := NewEvaluator().Evaluate(p.ParsePackage(`99999999999999999999 - 99999999999999999998;`, "main"))
+	if _, ok := out.Object.(*object.Integer); !ok {
+		t.Fatalf("expected demotion to integer got %T", out.Object)
+	}
+}
+
+func TestRuntimeFailures(t *testing.T) {
+	eval := NewEvaluator()
+	_ = eval.Builtins.Register(&object.Builtin{
+		Name: "crash",
+		Fun: func(rt *object.Runtime, args ...object.Object) (object.Object, error) {
+			var elements []object.Object
+			return elements[1], nil
+		},
+	})
+	var tests = []struct {
+		code string
+		err  string
+	}{
+		{`1 / 0;`, "* Error at L1 division by zero"},
+		{`99999999999999999999 / (1 - 1);`, "* Error at L1 division by zero"},
+		{`fun f(n) {
+			return 10 / n;
+		}
+		f(0);`, "* Error at L2 division by zero"},
+		{`fun f() {
+			crash();
+		}
+		f();`, "* Error at L2 internal error: runtime error: index out of range [1] with length 0"},
+	}
+	p := parser.NewParser()
+	for _, test := range tests {
+		out := eval.Evaluate(p.ParsePackage(test.code, "main"))
+		if out.Errors.Len() != 1 || out.Errors[0].String() != test.err {
+			t.Fatalf("%s expected %q got %q", test.code, test.err, out.Errors.String())
+		}
+		if eval.Context().Parent() != nil || len(eval.Frames()) != 0 {
+			t.Fatalf("%s left %d frames behind", test.code, len(eval.Frames()))
+		}
+	}
+}
+
+func TestTailCalls(t *testing.T) {
+	var tests = []struct {
+		code     string
+		expected string
+	}{
+		{`fun count(n, acc) {
+			if (n == 0) { return acc; }
+			return count(n - 1, acc + 1);
+		}
+		count(1000000, 0);`, "1000000"},
+		{`fun even(n) { if (n == 0) { return true; } return odd(n - 1); }
+		fun odd(n) { if (n == 0) { return false; } return even(n - 1); }
+		even(1000001);`, "false"},
+		{`fun spin(n) {
+			loop {
+				if (n > 0) { return spin(n - 1); }
+				return len("done");
+			}
+		}
+		spin(1000000);`, "4"},
+		{`fun g() { return x; } fun f() { var x = 5; return g(); } f();`, "5"},
+		{`fun h(n) { if (n == 0) { return x + y; } var y = n; return h(n - 1); }
+		fun f() { var x = 5; return h(3); }
+		f();`, "6"},
+	}
+	p := parser.NewParser()
+	for _, test := range tests {
+		eval := NewEvaluator()
+		out := eval.Evaluate(p.ParsePackage(test.code, "main"))
+		if out.Errors.Len() > 0 || out.Object == nil || out.Object.Inspect() != test.expected {
+			t.Fatalf("%s expected %s got %v %s", test.code, test.expected, out.Object, out.Errors.String())
+		}
+		if eval.Context().Parent() != nil || len(eval.Frames()) != 0 {
+			t.Fatalf("%s left %d frames behind", test.code, len(eval.Frames()))
+		}
+	}
+	// Calls out of tail position still nest
+	out := NewEvaluator().Evaluate(p.ParsePackage(`fun sum(n) {
+		if (n == 0) { return 0; }
+		return n + sum(n - 1);
+	}
+	sum(1000000);`, "main"))
+	if out.Limit == nil || out.Limit.Limit != DepthLimit {
+		t.Fatalf("expected depth limit got %v %s", out.Object, out.Errors.String())
+	}
+}
+
+func TestLimits(t *testing.T) {
+	var tests = []struct {
+		code   string
+		limits Limits
+		limit  string
+	}{
+		{`loop { }`, Limits{Steps: 1000}, StepLimit},
+		{`fun down(n) { return 1 + down(n + 1); } down(0);`, Limits{Depth: 100}, DepthLimit},
+		{`fun down(n) { return 1 + down(n + 1); } down(0);`, Limits{}, DepthLimit},
+		{`var s = "data"; loop { s = s + s; }`, Limits{Memory: 1 << 20}, MemoryLimit},
+		{`fun wait() { loop { } } wait();`, Limits{}, TimeLimit},
+		{`fun ones() { loop { yield 1; } } for x in ones() { }`, Limits{Steps: 1000}, StepLimit},
+		{`fun spin() { loop { } yield 1; } next(spin());`, Limits{Steps: 1000}, StepLimit},
+	}
+	p := parser.NewParser()
+	for _, test := range tests {
+		eval := NewEvaluator()
+		if test.limits != (Limits{}) {
+			eval.Limits = test.limits
+		}
+		timeout := time.Minute
+		if test.limit == TimeLimit {
+			timeout = 50 * time.Millisecond
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		out := eval.EvaluateContext(ctx, p.ParsePackage(test.code, "main"))
+		cancel()
+		if out.Limit == nil || out.Limit.Limit != test.limit {
+			t.Fatalf("%s expected %s limit got %v %s", test.code, test.limit, out.Limit, out.Errors.String())
+		}
+		if out.Errors.Len() != 1 || out.Errors[0].Message() != out.Limit.Error() {
+			t.Fatalf("%s expected only the limit error got %s", test.code, out.Errors.String())
+		}
+		// The runtime is left usable after being stopped
+		if eval.Context().Parent() != nil || len(eval.Frames()) != 0 {
+			t.Fatalf("%s left %d frames behind", test.code, len(eval.Frames()))
+		}
+		out = eval.Evaluate(p.ParsePackage(`var i = 0; loop { i = i + 1; if (i == 10) { break; } } i;`, "main"))
+		if out.Errors.Len() > 0 || out.Object.Inspect() != "10" {
+			t.Fatalf("%s broke the runtime: %v %s", test.code, out.Object, out.Errors.String())
+		}
+	}
+}
+
+func TestConstants(t *testing.T) {
+	var tests = []struct {
+		code string
+		err  string
+	}{
+		{`const a = 1;`, ""},
+		{`a = 2;`, "* Error at L1 cannot assign to constant \"a\"\n"},
+		{`var a = 2;`, "* Error at L1 \"a\" is already declared\n"},
+		{`{ var a = 2; a = 3; }`, ""},
+		{`fun f() { a = 4; } f();`, "* Error at L1 cannot assign to constant \"a\"\n"},
+		{`fun f() { return a; }`, ""},
+		{`f = 5;`, "* Error at L1 cannot assign to constant \"f\"\n"},
+		{`const f = 5;`, "* Error at L1 \"f\" is already declared\n"},
+	}
+	eval := NewEvaluator()
+	p := parser.NewParser()
+	for _, test := range tests {
+		out := eval.Evaluate(p.ParsePackage(test.code, "main"))
+		if out.Errors.String() != test.err {
+			t.Fatalf("%s expected %q got %q", test.code, test.err, out.Errors.String())
+		}
+	}
+	if out := eval.Evaluate(p.ParsePackage(`f();`, "main")); object.Inspect(out.Object) != "1" {
+		t.Fatalf("expected constant to keep its value got %s", object.Inspect(out.Object))
+	}
+}
+
+// evalCase is a program expecting the value of its last
+// statement, when given, and the errors it fails with.
+type evalCase struct {
+	code   string
+	result string
+	err    string
+}
+
+// runCases evaluates every case with an evaluator of its own,
+// prepared by setup when given.
+func runCases(t *testing.T, setup func(*Evaluator), tests []evalCase) {
+	p := parser.NewParser()
+	for _, test := range tests {
+		eval := NewEvaluator()
+		if setup != nil {
+			setup(eval)
+		}
+		out := eval.Evaluate(p.ParsePackage(test.code, "main"))
+		if out.Errors.String() != test.err {
+			t.Fatalf("%s expected errors %q got %q", test.code, test.err, out.Errors.String())
+		}
+		if test.result != "" && object.Inspect(out.Object) != test.result {
+			t.Fatalf("%s expected %s got %s", test.code, test.result, object.Inspect(out.Object))
+		}
+	}
+}
+
+// prelude sets evaluators up with the definitions in code.
+func prelude(code string) func(*Evaluator) {
+	return func(eval *Evaluator) {
+		eval.Evaluate(parser.NewParser().ParsePackage(code, "main"))
+	}
+}
+
+func TestUpdates(t *testing.T) {
+	runCases(t, nil, []evalCase{
+		{code: `var a = 9223372036854775807; a++; a;`, result: "9223372036854775808"},
+		{code: `var calls = 0; fun next() { calls++; return calls; }
+		var a = 10; a += next(); a += next(); calls * 100 + a;`, result: "213"},
+		{code: `var a = 1; a /= 0; a;`, result: "1", err: "* Error at L1 division by zero\n"},
+	})
+}
+
+func TestWhileFor(t *testing.T) {
+	runCases(t, nil, []evalCase{
+		{code: `var a = 0; while (false) { a++; } a;`, result: "0"},
+		{code: `var i = 7; for (var i = 0; i < 2; i++) { } i;`, result: "7"},
+		{code: `fun find() { for (var i = 0; i < 10; i++) { if (i == 4) { return i; } } } find();`, result: "4"},
+		{code: `while (1) { }`, err: "* Error at L1 expected conditional or boolean\n"},
+	})
+}
+
+func TestForIn(t *testing.T) {
+	hash := object.NewMap()
+	hash.Set(&object.String{Value: "a"}, &object.Integer{Value: 1})
+	hash.Set(&object.String{Value: "b"}, &object.Integer{Value: 2})
+	runCases(t, func(eval *Evaluator) {
+		eval.SetValue("array", &object.Array{Elements: []object.Object{
+			&object.Integer{Value: 1}, &object.Integer{Value: 2}, &object.Integer{Value: 3}}})
+		eval.SetValue("hash", hash)
+	}, []evalCase{
+		{code: `var s = ""; for c in "añb" { s = c + s; } s;`, result: `"bña"`},
+		{code: `var t = 0; for i in range(5, 0) { t++; } t;`, result: "0"},
+		{code: `var t = 0; for i in range(0, 9223372036854775807, 4611686018427387904) { t++; } t;`, result: "2"},
+		{code: `var t = 0; for i in range(0, 1000000000000) { if (i == 3) { break; } t += i; } t;`, result: "3"},
+		{code: `var t = 0; for x in array { t += x; } t;`, result: "6"},
+		{code: `var s = ""; var t = 0; for k, v in hash { s += k; t += v; } t * 100 + len(s);`, result: "302"},
+		{code: `var t = 0; for k in hash { t++; } t;`, result: "2"},
+		{code: `for x in 5 { } for x in range(0, 1, 0) { }`,
+			err: "* Error at L1 integer is not iterable\n* Error at L1 \"range\" failed: range step must not be zero\n"},
+	})
+}
+
+func TestContinueLabels(t *testing.T) {
+	runCases(t, nil, []evalCase{
+		{code: `var t = 0; for (var i = 0; i < 6; i++) { if (i == 2) { continue; } t += i; } t;`, result: "13"},
+		{code: `var t = 0; var i = 0; while (i < 5) { i++; if (i == 3) { continue; } t += i; } t;`, result: "12"},
+		{code: `var t = 0; a: for i in range(0, 2) { b: for j in range(0, 5) { if (j == 2) { break b; } t++; } t += 100; } t;`, result: "204"},
+		{code: `fun f() { outer: loop { loop { return 7; } } } f();`, result: "7"},
+		{code: `var t = 0; loop { t++; if (t == 3) { break; } continue; } t;`, result: "3"},
+		{code: `loop { missing; continue; }`, err: "* Error at L1 identifier \"missing\" not found\n"},
+	})
+}
+
+func TestConditionalChains(t *testing.T) {
+	runCases(t, nil, []evalCase{
+		{code: `var a = 4; if (a == 1) { 1; } else if (a == 2) { 2; }`, result: "null"},
+		{code: `var x = if (false) { 1 } else { var y = 5; y * 2 }; x;`, result: "10"},
+		{code: `fun pick(a) { return if (a) { "yes" } else { "no" }; } pick(false);`, result: `"no"`},
+		{code: `var u = if (false) { 1 }; u;`, result: "null"},
+		{code: `var a = 1; var u = if (true) { a = 2; }; u;`, result: "null"},
+		{code: `var u = if (true) { if (false) { 1 } }; u;`, result: "null"},
+	})
+}
+
+func TestMatch(t *testing.T) {
+	pair := &object.Array{Elements: []object.Object{&object.Integer{Value: 3}, &object.String{Value: "b"}}}
+	hash := object.NewMap()
+	hash.Set(&object.String{Value: "kind"}, &object.String{Value: "point"})
+	hash.Set(&object.String{Value: "at"}, pair)
+	runCases(t, func(eval *Evaluator) {
+		eval.SetValue("pair", pair)
+		eval.SetValue("hash", hash)
+	}, []evalCase{
+		{code: `match (-3) { -3 => true, _ => false }`, result: "true"},
+		{code: `var x = 1; var y = match (10) { x => x * 2 } + x; y;`, result: "21"},
+		{code: `match (pair) { [n] => n, [n, "a"] => -n, [n, "b"] => n * 10 }`, result: "30"},
+		{code: `match (hash) { {"kind": "line"} => 0, {"kind": "point", "at": [x, _]} => x }`, result: "3"},
+		{code: `match (hash) { {"missing": _} => 0, _ => 1 }`, result: "1"},
+		{code: `var r = match (true) { false => 0, true => { var a = 4; a + 1 } }; r;`, result: "5"},
+		{code: `fun f(n) { match (n) { 0 => { return "zero"; }, _ => { return "other"; } } } f(0);`, result: `"zero"`},
+		{code: `match ("c") { "a" => 1 }`, err: "* Error at L1 no pattern matches \"c\"\n"},
+	})
+}
+
+func TestStructs(t *testing.T) {
+	runCases(t, prelude("struct Point { x, y }\nstruct Box { v }\n"), []evalCase{
+		{code: `struct A { v } A { v: 1 } == Box { v: 1 };`, result: "false"},
+		{code: `var b = Box { v: Box { v: 7 } }; b.v.v = 8; b;`, result: "Box{v: Box{v: 8}}"},
+		{code: `fun f() { return Box { v: "ab" }; } f().v;`, result: `"ab"`},
+		{code: `var a = Box { v: 1 }; var b = a; b.v = 2; a.v;`, result: "2"},
+		{code: `Point { z: 1 };`, err: "* Error at L1 Point has no field \"z\"\n"},
+		{code: `var n = 1; n.x = 2;`, err: "* Error at L1 integer has no field \"x\"\n"},
+		{code: `var n = 1; n { x: 2 };`, err: "* Error at L1 \"n\" is not a struct type\n"},
+	})
+}
+
+func TestClasses(t *testing.T) {
+	runCases(t, prelude(`
+class Counter {
+	fun init(start) { self.count = start; }
+	fun add(n) { self.count += n; return self; }
+	fun get() { return self.count; }
+}
+class Named : Counter {
+	fun init(name) { super.init(0); self.name = name; }
+	fun get() { return self.name + ":" + str(super.get()); }
+}
+fun str(n) { return match (n) { 0 => "0", 1 => "1", 2 => "2", _ => "many" }; }
+`), []evalCase{
+		{code: `Named("a").add(2).get();`, result: `"a:2"`},
+		{code: `var n = Named("a"); var get = n.get; n.add(1); get();`, result: `"a:1"`},
+		{code: `var c = Counter(0); c.extra = 1; c;`, result: "Counter{count: 0, extra: 1}"},
+		{code: `class Counter { fun get() { return 9; } } Counter().get();`, result: "9"},
+		{code: `Counter();`, err: "* Error at L1 expected 1 function parameters\n"},
+		{code: `Counter(0).reset();`, err: "* Error at L1 Counter has no field \"reset\"\n"},
+		{code: `var x = 1; class Bad : x { }`, err: "* Error at L1 \"x\" is not a class\n"},
+		{code: `class Bad : Missing { }`, err: "* Error at L1 identifier \"Missing\" not found\n"},
+	})
+}
+
+func TestEnums(t *testing.T) {
+	runCases(t, prelude("enum Color { Red, Green, Blue }\nenum Result { Ok(value), Err(error) }\n"), []evalCase{
+		{code: `Color.Red != Color.Blue;`, result: "true"},
+		{code: `var old = Color.Red; enum Color { Red } old == Color.Red;`, result: "false"},
+		{code: `match (Result.Ok(0)) { Result.Ok(0) => "zero", Result.Ok(_) => "other" }`, result: `"zero"`},
+		{code: `match (5) { Color.Red => 1, _ => 2 }`, result: "2"},
+		{code: `Color.Purple;`, err: "* Error at L1 Color has no variant \"Purple\"\n"},
+		{code: `Result.Ok(1, 2);`, err: "* Error at L1 expected 1 function parameters\n"},
+		{code: `Color.Red(1);`, err: "* Error at L1 identifier \"Red\" is not a function\n"},
+		{code: `var x = 1; match (1) { x.A => 1 }`, err: "* Error at L1 \"x\" is not an enum\n"},
+	})
+}
+
+func TestGenerators(t *testing.T) {
+	var gens = "fun count(n) { var i = 0; while (i < n) { yield i; i++; } return 99; }\n"
+	runCases(t, prelude(gens), []evalCase{
+		{code: `var g = count(1); next(g); next(g);`, result: "null"},
+		{code: `fun nested() { var a = count(2); yield next(a); var b = count(3); next(b); yield next(b) + next(a); } var g = nested(); next(g) + next(g);`, result: "2"},
+		{code: `fun later() { return count(3); } var g = later(); next(g); next(g);`, result: "1"},
+		{code: `var i = 5; var g = count(2); next(g); i;`, result: "5"},
+		{code: `fun bad() { yield missing; } next(bad());`, err: "* Error at L1 identifier \"missing\" not found\n"},
+	})
+	// Generators are resumed across evaluations until closed
+	p := parser.NewParser()
+	eval := NewEvaluator()
+	out := eval.Evaluate(p.ParsePackage(gens+"var g = count(3); next(g);", "main"))
+	if out.Errors.Len() != 0 {
+		t.Fatal(out.Errors.String())
+	}
+	out = eval.Evaluate(p.ParsePackage("next(g);", "main"))
+	if out.Errors.Len() != 0 || object.Inspect(out.Object) != "1" || eval.Context().Parent() != nil {
+		t.Fatalf("expected resumed generator got %s %s", object.Inspect(out.Object), out.Errors.String())
+	}
+	eval.Close()
+	out = eval.Evaluate(p.ParsePackage("next(g);", "main"))
+	if out.Errors.Len() != 0 || object.Inspect(out.Object) != "null" {
+		t.Fatalf("expected stopped generator got %s %s", object.Inspect(out.Object), out.Errors.String())
+	}
+}
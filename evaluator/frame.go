@@ -0,0 +1,63 @@
+package evaluator
+
+import (
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+	"github.com/Onelio/Eldrlang/util"
+)
+
+type Frame struct {
+	Name    string
+	Call    lexer.Token
+	Context *object.Context
+}
+
+// Hook is called before every statement is evaluated.
+type Hook func(node parser.Node)
+
+func (e *Evaluator) SetHook(hook Hook) {
+	e.hook = hook
+}
+
+// Frames returns the active function calls, innermost last.
+func (e *Evaluator) Frames() []Frame {
+	frames := make([]Frame, len(e.frames))
+	copy(frames, e.frames)
+	return frames
+}
+
+// EvaluateExpression evaluates a node in the current context
+// without triggering the hook, keeping its errors apart from
+// the ones of the running evaluation.
+func (e *Evaluator) EvaluateExpression(node parser.Node) (object.Object, util.Errors) {
+	errors, hook := e.errors, e.hook
+	e.errors, e.hook = nil, nil
+	result := e.EvaluateNode(node)
+	exprErrors := e.errors
+	e.errors, e.hook = errors, hook
+	return result, exprErrors
+}
+
+func (e *Evaluator) evalStatement(node parser.Node) object.Object {
+	if e.hook != nil {
+		e.hook(node)
+	}
+	return e.EvaluateNode(node)
+}
+
+// Apply calls a function object, typically from a builtin,
+// keeping its errors apart from the ones of the running
+// evaluation.
+func (e *Evaluator) Apply(fun object.Object, args ...object.Object) (object.Object, util.Errors) {
+	call := lexer.Token{Type: lexer.IDENT, Literal: fun.Inspect()}
+	if f, ok := fun.(*object.Function); ok {
+		call.Literal = f.Name
+	}
+	errors := e.errors
+	e.errors = nil
+	result := e.applyFunction(fun, args, call)
+	funErrors := e.errors
+	e.errors = errors
+	return result, funErrors
+}
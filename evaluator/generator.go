@@ -0,0 +1,135 @@
+package evaluator
+
+import (
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+)
+
+// generator runs the body of a generator function on a
+// goroutine of its own, taking turns with the code resuming
+// it so only one of them uses the evaluator at a time. While
+// suspended, it keeps the context and frames it was in.
+type generator struct {
+	fun     *object.Function
+	params  []object.Object
+	call    lexer.Token
+	context *object.Context
+	frames  []Frame
+	resume  chan bool
+	yield   chan yielded
+	started bool
+	done    bool
+}
+
+// yielded is what a generator hands back when suspended, or
+// done with the panic it ended with, if any.
+type yielded struct {
+	value object.Object
+	done  bool
+	panic interface{}
+}
+
+// stopGenerator unwinds the goroutine of a generator that
+// won't be resumed again.
+type stopGenerator struct{}
+
+func (e *Evaluator) newGenerator(fun *object.Function, params []object.Object, call lexer.Token) object.Object {
+	g := &generator{
+		fun:     fun,
+		params:  params,
+		call:    call,
+		context: e.Context(),
+		resume:  make(chan bool),
+		yield:   make(chan yielded),
+	}
+	return e.allocate(object.NewGenerator(fun.Name, func() (object.Object, bool) {
+		return e.resume(g)
+	}), call)
+}
+
+func (e *Evaluator) resume(g *generator) (object.Object, bool) {
+	if g.done {
+		return nil, false
+	}
+	y := e.enter(g, func() {
+		if !g.started {
+			g.started = true
+			e.generators = append(e.generators, g)
+			go e.runGenerator(g)
+		} else {
+			g.resume <- true
+		}
+	})
+	if y.done {
+		g.done = true
+		e.forget(g)
+	}
+	if y.panic != nil {
+		panic(y.panic)
+	}
+	return y.value, !y.done
+}
+
+// enter lets a generator run in place of the caller until it
+// is suspended again, restoring the state of the caller.
+func (e *Evaluator) enter(g *generator, signal func()) yielded {
+	var (
+		caller  = e.Switch(g.context)
+		frames  = e.frames
+		flow    = e.flow
+		yielder = e.yielder
+	)
+	e.frames = append(frames[:len(frames):len(frames)], g.frames...)
+	e.flow, e.yielder = flowNone, g
+	signal()
+	y := <-g.yield
+	g.context = e.Switch(caller)
+	g.frames = append([]Frame(nil), e.frames[len(frames):]...)
+	e.frames, e.flow, e.yielder = frames, flow, yielder
+	return y
+}
+
+func (e *Evaluator) runGenerator(g *generator) {
+	defer func() {
+		r := recover()
+		if _, stopped := r.(stopGenerator); stopped {
+			r = nil
+		}
+		g.yield <- yielded{done: true, panic: r}
+	}()
+	e.runFunction(g.fun, g.params, g.call)
+}
+
+func (e *Evaluator) evalYield(y *parser.Yield) {
+	var value object.Object
+	if y.Value != nil {
+		value = e.EvaluateNode(y.Value)
+	}
+	g := e.yielder
+	g.yield <- yielded{value: value}
+	if !<-g.resume {
+		panic(stopGenerator{})
+	}
+}
+
+func (e *Evaluator) forget(g *generator) {
+	for i, started := range e.generators {
+		if started == g {
+			e.generators = append(e.generators[:i], e.generators[i+1:]...)
+			return
+		}
+	}
+}
+
+// stopGenerators ends the goroutines of the generators left
+// suspended, which can't be resumed afterwards.
+func (e *Evaluator) stopGenerators() {
+	for _, g := range e.generators {
+		if !g.done {
+			e.enter(g, func() { g.resume <- false })
+			g.done = true
+		}
+	}
+	e.generators = nil
+}
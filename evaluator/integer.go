@@ -0,0 +1,98 @@
+package evaluator
+
+import (
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/util"
+	"math"
+	"math/big"
+)
+
+// evalIntegerInfix operates on int64 values while the result
+// fits in one, and through math/big otherwise.
+func (e *Evaluator) evalIntegerInfix(token lexer.Token, operator string, left, right object.Object) object.Object {
+	l, lok := left.(*object.Integer)
+	r, rok := right.(*object.Integer)
+	// Big integers are never zero, as those are demoted
+	if operator == "/" && rok && r.Value == 0 {
+		err := util.NewError(token, util.DividedByZero)
+		e.errors.Add(err)
+		return nil
+	}
+	if lok && rok {
+		if result, ok := intInfix(operator, l.Value, r.Value); ok {
+			return result
+		}
+	}
+	x, _ := object.ToBig(left)
+	y, valid := object.ToBig(right)
+	if !valid {
+		err := util.NewError(token, util.InvalidOpComb)
+		e.errors.Add(err)
+		return nil
+	}
+	switch operator {
+	case "+":
+		return object.FromBig(new(big.Int).Add(x, y))
+	case "-":
+		return object.FromBig(new(big.Int).Sub(x, y))
+	case "*":
+		return object.FromBig(new(big.Int).Mul(x, y))
+	case "/":
+		return object.FromBig(new(big.Int).Quo(x, y))
+	case "<":
+		return &object.Boolean{Value: x.Cmp(y) < 0}
+	case ">":
+		return &object.Boolean{Value: x.Cmp(y) > 0}
+	case "==":
+		return &object.Boolean{Value: x.Cmp(y) == 0}
+	case "!=":
+		return &object.Boolean{Value: x.Cmp(y) != 0}
+	default:
+		err := util.NewError(token, util.InvalidOpForO)
+		e.errors.Add(err)
+		return nil
+	}
+}
+
+// intInfix returns false when the result would overflow or
+// the operator is unknown, leaving those to math/big.
+func intInfix(operator string, a, b int64) (object.Object, bool) {
+	switch operator {
+	case "+":
+		c := a + b
+		if (c > a) != (b > 0) {
+			return nil, false
+		}
+		return &object.Integer{Value: c}, true
+	case "-":
+		c := a - b
+		if (c < a) != (b > 0) {
+			return nil, false
+		}
+		return &object.Integer{Value: c}, true
+	case "*":
+		if a == 0 || b == 0 {
+			return &object.Integer{Value: 0}, true
+		}
+		c := a * b
+		if c/b != a || a == -1 && b == math.MinInt64 || b == -1 && a == math.MinInt64 {
+			return nil, false
+		}
+		return &object.Integer{Value: c}, true
+	case "/":
+		if a == math.MinInt64 && b == -1 {
+			return nil, false
+		}
+		return &object.Integer{Value: a / b}, true
+	case "<":
+		return &object.Boolean{Value: a < b}, true
+	case ">":
+		return &object.Boolean{Value: a > b}, true
+	case "==":
+		return &object.Boolean{Value: a == b}, true
+	case "!=":
+		return &object.Boolean{Value: a != b}, true
+	}
+	return nil, false
+}
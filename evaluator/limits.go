@@ -0,0 +1,147 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+	"github.com/Onelio/Eldrlang/util"
+)
+
+// DefaultDepth keeps runaway recursion from exhausting the
+// Go stack of the host.
+const DefaultDepth = 10000
+
+const (
+	StepLimit   = "step"
+	DepthLimit  = "depth"
+	MemoryLimit = "memory"
+	TimeLimit   = "time"
+)
+
+// Limits bounds the resources of every evaluation, a zero
+// field not being enforced. Memory counts the approximate
+// bytes of all the objects created, even if no longer used.
+type Limits struct {
+	Steps  int64
+	Depth  int
+	Memory int64
+}
+
+// LimitError is the error an evaluation stopped with after
+// exceeding a limit, Err being the context error for time.
+type LimitError struct {
+	Limit string
+	Token lexer.Token
+	Err   error
+}
+
+func (e *LimitError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf(util.EvalCancelled, e.Err)
+	}
+	return fmt.Sprintf(util.LimitExceeded, e.Limit)
+}
+
+// Halt can be panicked with from a hook to end the evaluation.
+// It is panicked with again once the evaluator is restored.
+type Halt struct {
+	Reason error
+}
+
+type usage struct {
+	ctx    context.Context
+	done   <-chan struct{}
+	node   parser.Node
+	steps  int64
+	memory int64
+}
+
+// run evaluates with fresh usage counters. When a limit or an
+// unexpected panic stops the evaluation, the contexts, frames
+// and generators left behind are dropped so the runtime can be
+// used again.
+func (e *Evaluator) run(ctx context.Context, eval func() object.Object) (out *Output) {
+	var (
+		base   = e.Context()
+		frames = len(e.frames)
+	)
+	e.usage = usage{ctx: ctx, done: ctx.Done()}
+	out = &Output{}
+	defer func() {
+		if r := recover(); r != nil {
+			e.stopGenerators()
+			e.Unwind(base)
+			e.frames = e.frames[:frames]
+			e.flow, e.target, e.tail = flowNone, "", nil
+			switch r := r.(type) {
+			case Halt:
+				e.errors.Clear()
+				panic(r)
+			case *LimitError:
+				out.Limit = r
+				e.errors.Add(util.NewError(r.Token, "%s", r.Error()))
+			default:
+				token := parser.TokenOf(e.usage.node)
+				e.errors.Add(util.NewError(token, util.InternalError, r))
+			}
+		}
+		out.Errors = e.errors
+		e.errors.Clear()
+	}()
+	out.Object = eval()
+	return out
+}
+
+func (e *Evaluator) step(node parser.Node) {
+	e.usage.node = node
+	e.usage.steps++
+	if e.Limits.Steps > 0 && e.usage.steps > e.Limits.Steps {
+		e.stop(StepLimit, parser.TokenOf(node), nil)
+	}
+	if e.usage.done != nil {
+		select {
+		case <-e.usage.done:
+			e.stop(TimeLimit, parser.TokenOf(node), e.usage.ctx.Err())
+		default:
+		}
+	}
+}
+
+func (e *Evaluator) allocate(obj object.Object, token lexer.Token) object.Object {
+	if obj == nil || e.Limits.Memory <= 0 {
+		return obj
+	}
+	e.usage.memory += sizeOf(obj)
+	if e.usage.memory > e.Limits.Memory {
+		e.stop(MemoryLimit, token, nil)
+	}
+	return obj
+}
+
+func (e *Evaluator) stop(limit string, token lexer.Token, err error) {
+	panic(&LimitError{Limit: limit, Token: token, Err: err})
+}
+
+// sizeOf approximates the bytes used by an object, without
+// the elements of collections as they are already counted.
+func sizeOf(obj object.Object) int64 {
+	switch o := obj.(type) {
+	case *object.String:
+		return 16 + int64(len(o.Value))
+	case *object.BigInt:
+		return 32 + int64(len(o.Value.Bits()))*8
+	case *object.Array:
+		return 24 + 16*int64(len(o.Elements))
+	case *object.Map:
+		return 48 + 48*int64(o.Len())
+	case *object.Struct:
+		return 24 + 16*int64(len(o.Values))
+	case *object.Instance:
+		return 64
+	case *object.EnumValue:
+		return 24 + 16*int64(len(o.Values))
+	}
+	return 16
+}
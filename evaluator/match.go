@@ -0,0 +1,93 @@
+package evaluator
+
+import (
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+	"github.com/Onelio/Eldrlang/util"
+)
+
+// evalMatch tries the arms in order, each one in a fresh
+// context the patterns bind their variables in, failing when
+// none matches.
+func (e *Evaluator) evalMatch(match *parser.Match) object.Object {
+	errors := e.errors.Len()
+	value := e.EvaluateNode(match.Value)
+	if e.errors.Len() > errors {
+		return nil
+	}
+	for _, arm := range match.Arms {
+		if result, matched := e.evalMatchArm(arm, value); matched {
+			return result
+		}
+		if e.errors.Len() > errors {
+			return nil
+		}
+	}
+	err := util.NewError(match.Token, util.NoMatchingArm, object.Inspect(value))
+	e.errors.Add(err)
+	return nil
+}
+
+func (e *Evaluator) evalMatchArm(arm *parser.MatchArm, value object.Object) (object.Object, bool) {
+	for _, pattern := range arm.Patterns {
+		e.PushChild()
+		if e.matchPattern(pattern, value) && e.matchGuard(arm) {
+			result := e.EvaluateNode(arm.Body)
+			e.PopChild()
+			return result, true
+		}
+		e.PopChild()
+	}
+	return nil, false
+}
+
+func (e *Evaluator) matchGuard(arm *parser.MatchArm) bool {
+	if arm.Guard == nil {
+		return true
+	}
+	cond, valid := e.EvaluateNode(arm.Guard).(*object.Boolean)
+	if !valid {
+		err := util.NewError(arm.Token, util.ExpectedCondV)
+		e.errors.Add(err)
+		return false
+	}
+	return cond.Value
+}
+
+// matchPattern tells whether value matches a pattern, binding
+// its variables in the current context.
+func (e *Evaluator) matchPattern(pattern parser.Node, value object.Object) bool {
+	switch p := pattern.(type) {
+	case *parser.Identifier:
+		if p.Value != "_" {
+			e.SetValue(p.Value, value)
+		}
+		return true
+	case *parser.ArrayPattern:
+		array, ok := value.(*object.Array)
+		if !ok || len(array.Elements) != len(p.Elements) {
+			return false
+		}
+		for i, elem := range p.Elements {
+			if !e.matchPattern(elem, array.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *parser.VariantPattern:
+		return e.matchVariant(p, value)
+	case *parser.MapPattern:
+		hash, ok := value.(*object.Map)
+		if !ok {
+			return false
+		}
+		for i, key := range p.Keys {
+			elem := hash.Get(e.EvaluateNode(key))
+			if elem == nil || !e.matchPattern(p.Values[i], elem) {
+				return false
+			}
+		}
+		return true
+	}
+	return object.Equals(e.EvaluateNode(pattern), value)
+}
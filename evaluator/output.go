@@ -8,6 +8,7 @@ import (
 type Output struct {
 	object.Object
 	Errors util.Errors
+	Limit  *LimitError
 }
 
 func (o *Output) String() string {
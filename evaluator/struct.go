@@ -0,0 +1,86 @@
+package evaluator
+
+import (
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+	"github.com/Onelio/Eldrlang/util"
+)
+
+func (e *Evaluator) evalStruct(s *parser.Struct) {
+	def := &object.StructType{Name: s.Name.Value}
+	for _, field := range s.Fields {
+		def.Fields = append(def.Fields, field.Value)
+	}
+	e.defineConstant(s.Name, def, func(old object.Object) bool {
+		_, same := old.(*object.StructType)
+		return same
+	})
+}
+
+func (e *Evaluator) evalStructLiteral(literal *parser.StructLiteral) object.Object {
+	stored := e.evalIdentifier(literal.Name)
+	if stored == nil {
+		return nil
+	}
+	def, ok := stored.(*object.StructType)
+	if !ok {
+		err := util.NewError(literal.Name.Token, util.NotAStructTyp, literal.Name.Value)
+		e.errors.Add(err)
+		return nil
+	}
+	obj := def.New()
+	for i, field := range literal.Fields {
+		value := e.EvaluateNode(literal.Values[i])
+		if !obj.Set(field.Value, value) {
+			err := util.NewError(field.Token, util.UnknownFieldN, def.Name, field.Value)
+			e.errors.Add(err)
+			return nil
+		}
+	}
+	return e.allocate(obj, literal.Token)
+}
+
+func (e *Evaluator) evalField(field *parser.Field) object.Object {
+	switch owner := field.Object.(type) {
+	case *parser.Super:
+		return e.evalSuper(field)
+	case *parser.Identifier:
+		if enum, ok := e.GetValue(owner.Value).(*object.Enum); ok {
+			return e.evalVariant(enum, field.Name)
+		}
+	}
+	obj := e.fieldOwner(field)
+	if obj == nil {
+		return nil
+	}
+	value := obj.Get(field.Name.Value)
+	if value == nil {
+		e.unknownField(field, obj.Name())
+	}
+	return value
+}
+
+// fieldOwner evaluates, once, the record a field is accessed
+// on, failing when it isn't one.
+func (e *Evaluator) fieldOwner(field *parser.Field) object.Record {
+	errors := e.errors.Len()
+	owner := e.EvaluateNode(field.Object)
+	if e.errors.Len() > errors {
+		return nil
+	}
+	obj, ok := owner.(object.Record)
+	if !ok {
+		name := string(object.NULL)
+		if owner != nil {
+			name = string(owner.Type())
+		}
+		e.unknownField(field, name)
+		return nil
+	}
+	return obj
+}
+
+func (e *Evaluator) unknownField(field *parser.Field, owner string) {
+	err := util.NewError(field.Name.Token, util.UnknownFieldN, owner, field.Name.Value)
+	e.errors.Add(err)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"github.com/Onelio/Eldrlang/evaluator"
+	"github.com/Onelio/Eldrlang/parser"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// TestGolden runs every program in testdata through the parser
+// and the evaluator, comparing its output, result and errors
+// against the golden file next to it.
+func TestGolden(t *testing.T) {
+	programs, err := filepath.Glob("testdata/*.eld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, program := range programs {
+		name := strings.TrimSuffix(filepath.Base(program), ".eld")
+		t.Run(name, func(t *testing.T) {
+			code, err := ioutil.ReadFile(program)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := runGolden(string(code))
+			golden := strings.TrimSuffix(program, ".eld") + ".golden"
+			if *update {
+				if err := ioutil.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(expected) {
+				t.Fatalf("output differs from %s\nexpected:\n%s\ngot:\n%s", golden, expected, got)
+			}
+		})
+	}
+}
+
+// runGolden evaluates a program, returning its output, the value of
+// its last statement and its errors as golden file sections.
+func runGolden(code string) string {
+	var (
+		output bytes.Buffer
+		result string
+		errors string
+	)
+	parsed := parser.NewParser().ParsePackage(code, "main")
+	if parsed.Errors.Len() > 0 {
+		errors = parsed.Errors.String()
+	} else {
+		eval := evaluator.NewEvaluator()
+		eval.Stdin = strings.NewReader("")
+		eval.Stdout, eval.Stderr = &output, &output
+		out := eval.Evaluate(parsed)
+		eval.Close()
+		result, errors = out.String(), out.Errors.String()
+	}
+	return section("stdout", output.String()) + section("result", result) + section("errors", errors)
+}
+
+func section(name, content string) string {
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return "-- " + name + " --\n" + content
+}
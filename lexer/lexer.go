@@ -5,9 +5,10 @@ import (
 )
 
 type Lexer struct {
-	input []byte
-	index int
-	line  int
+	input     []byte
+	index     int
+	line      int
+	lineStart int
 }
 
 func NewLexer(input []byte) *Lexer {
@@ -16,11 +17,20 @@ func NewLexer(input []byte) *Lexer {
 
 func (l *Lexer) UpdateInput(input []byte) {
 	l.index = 0
+	l.line = 0
+	l.lineStart = 0
 	l.input = input
 }
 
 func (l *Lexer) NextToken() Token {
 	l.skipSpace()
+	column := l.index - l.lineStart
+	token := l.readToken()
+	token.Column = column
+	return token
+}
+
+func (l *Lexer) readToken() Token {
 	if l.index >= len(l.input) {
 		return Token{Type: EOF, Line: l.line, Literal: ""}
 	}
@@ -40,6 +50,27 @@ func (l *Lexer) NextToken() Token {
 	case 0x3D3E: // >= (Little Endian)
 		l.index += 2
 		return Token{Type: GTEQ, Line: l.line, Literal: ">="}
+	case 0x3D2B: // += (Little Endian)
+		l.index += 2
+		return Token{Type: PLUSASSIGN, Line: l.line, Literal: "+="}
+	case 0x3D2D: // -= (Little Endian)
+		l.index += 2
+		return Token{Type: MINUSASSIGN, Line: l.line, Literal: "-="}
+	case 0x3D2A: // *= (Little Endian)
+		l.index += 2
+		return Token{Type: ASTERISKASSIGN, Line: l.line, Literal: "*="}
+	case 0x3D2F: // /= (Little Endian)
+		l.index += 2
+		return Token{Type: SLASHASSIGN, Line: l.line, Literal: "/="}
+	case 0x2B2B: // ++ (Little Endian)
+		l.index += 2
+		return Token{Type: INCREMENT, Line: l.line, Literal: "++"}
+	case 0x2D2D: // -- (Little Endian)
+		l.index += 2
+		return Token{Type: DECREMENT, Line: l.line, Literal: "--"}
+	case 0x3E3D: // => (Little Endian)
+		l.index += 2
+		return Token{Type: ARROW, Line: l.line, Literal: "=>"}
 	}
 
 	// One character symbols check
@@ -68,6 +99,9 @@ func (l *Lexer) NextToken() Token {
 	case '>':
 		l.index += 1
 		return Token{Type: GT, Line: l.line, Literal: ">"}
+	case '|':
+		l.index += 1
+		return Token{Type: PIPE, Line: l.line, Literal: "|"}
 	case ',':
 		l.index += 1
 		return Token{Type: COMMA, Line: l.line, Literal: ","}
@@ -77,6 +111,9 @@ func (l *Lexer) NextToken() Token {
 	case ':':
 		l.index += 1
 		return Token{Type: COLON, Line: l.line, Literal: ":"}
+	case '.':
+		l.index += 1
+		return Token{Type: DOT, Line: l.line, Literal: "."}
 	case '(':
 		l.index += 1
 		return Token{Type: LPAREN, Line: l.line, Literal: "("}
@@ -128,9 +165,9 @@ func (l *Lexer) NextToken() Token {
 func (l *Lexer) PeekToken() Token {
 	// Workaround for cases where we don't
 	// want to move the cursor.
-	index := l.index
+	index, line, lineStart := l.index, l.line, l.lineStart
 	token := l.NextToken()
-	l.index = index
+	l.index, l.line, l.lineStart = index, line, lineStart
 	return token
 }
 
@@ -165,7 +202,11 @@ func (l *Lexer) readString() string {
 	l.index++ // Skip first quotes
 	start := l.index
 	end := l.index
-	for l.charAt(end) != '"' {
+	for end < len(l.input) && l.input[end] != '"' {
+		if l.input[end] == '\n' {
+			l.line++
+			l.lineStart = end + 1
+		}
 		end++
 	}
 	l.index = end + 1 // Skip second quotes
@@ -28,7 +28,13 @@ if (5 < 10) {
 "foobar"
 "foo bar"
 [1, 2];
-{"foo": "bar"} ñ`)
+{"foo": "bar"}
+a += 1 -= 2 *= 3 /= 4;
+a++ a--
+1 => 2 | 3
+struct p.x
+class self super enum yield
+ñ`)
 
 	tests := []struct {
 		expectedType    Type
@@ -120,6 +126,34 @@ if (5 < 10) {
 		{COLON, ":"},
 		{STRING, "bar"},
 		{RBRACE, "}"},
+		{IDENT, "a"},
+		{PLUSASSIGN, "+="},
+		{INTEGER, "1"},
+		{MINUSASSIGN, "-="},
+		{INTEGER, "2"},
+		{ASTERISKASSIGN, "*="},
+		{INTEGER, "3"},
+		{SLASHASSIGN, "/="},
+		{INTEGER, "4"},
+		{SEMICOLON, ";"},
+		{IDENT, "a"},
+		{INCREMENT, "++"},
+		{IDENT, "a"},
+		{DECREMENT, "--"},
+		{INTEGER, "1"},
+		{ARROW, "=>"},
+		{INTEGER, "2"},
+		{PIPE, "|"},
+		{INTEGER, "3"},
+		{STRUCT, "struct"},
+		{IDENT, "p"},
+		{DOT, "."},
+		{IDENT, "x"},
+		{CLASS, "class"},
+		{SELF, "self"},
+		{SUPER, "super"},
+		{ENUM, "enum"},
+		{YIELD, "yield"},
 		{ILLEGAL, "Ã"},
 		{ILLEGAL, "±"},
 		{EOF, ""},
@@ -1,5 +1,9 @@
 package lexer
 
+import (
+	"sort"
+)
+
 const (
 	EOF = iota
 	IDENT
@@ -16,6 +20,15 @@ const (
 	BANG
 	SLASH
 	ASTERISK
+	ARROW
+	PIPE
+
+	PLUSASSIGN
+	MINUSASSIGN
+	ASTERISKASSIGN
+	SLASHASSIGN
+	INCREMENT
+	DECREMENT
 
 	LT
 	LTEQ
@@ -28,6 +41,7 @@ const (
 	COMMA
 	SEMICOLON
 	COLON
+	DOT
 
 	LPAREN
 	RPAREN
@@ -38,6 +52,7 @@ const (
 
 	// Reserved Keywords
 	VARIABLE
+	CONSTANT
 	FUNCTION
 	RETURN
 	TRUE
@@ -46,27 +61,51 @@ const (
 	IF
 	ELSE
 	LOOP
+	WHILE
+	FOR
+	IN
 	BREAK
+	CONTINUE
+	MATCH
+	STRUCT
+	CLASS
+	SELF
+	SUPER
+	ENUM
+	YIELD
 )
 
 type Type int
 
 var keywords = map[string]Type{
-	"var":    VARIABLE,
-	"fun":    FUNCTION,
-	"return": RETURN,
-	"true":   TRUE,
-	"false":  FALSE,
-	"import": IMPORT,
-	"if":     IF,
-	"else":   ELSE,
-	"loop":   LOOP,
-	"break":  BREAK,
+	"var":      VARIABLE,
+	"const":    CONSTANT,
+	"fun":      FUNCTION,
+	"return":   RETURN,
+	"true":     TRUE,
+	"false":    FALSE,
+	"import":   IMPORT,
+	"if":       IF,
+	"else":     ELSE,
+	"loop":     LOOP,
+	"while":    WHILE,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"match":    MATCH,
+	"struct":   STRUCT,
+	"class":    CLASS,
+	"self":     SELF,
+	"super":    SUPER,
+	"enum":     ENUM,
+	"yield":    YIELD,
 }
 
 type Token struct {
 	Type
 	Line    int
+	Column  int
 	Literal string
 }
 
@@ -77,6 +116,15 @@ func LookupIdent(ident string) Type {
 	return IDENT
 }
 
+func Keywords() []string {
+	var names []string
+	for name := range keywords {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func isLetter(ch byte) bool {
 	return 'a' <= ch && ch <= 'z' ||
 		'A' <= ch && ch <= 'Z' ||
@@ -93,6 +141,7 @@ func (l *Lexer) skipSpace() {
 		switch {
 		case char == '\n':
 			l.line++
+			l.lineStart = l.index + 1
 			fallthrough
 		case char <= ' ':
 			l.index++
@@ -0,0 +1,365 @@
+package lsp
+
+import (
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/parser"
+	"strings"
+)
+
+type symbol struct {
+	name  string
+	kind  int
+	token lexer.Token
+	fun   *parser.Function
+	def   parser.Node
+	body  *scope
+	scope *scope
+}
+
+func (s *symbol) signature() string {
+	if s.kind == SymbolConstant {
+		return "const " + s.name
+	}
+	switch def := s.def.(type) {
+	case *parser.Struct, *parser.Enum:
+		return def.String()
+	case *parser.Class:
+		if def.Parent != nil {
+			return "class " + s.name + " : " + def.Parent.Value
+		}
+		return "class " + s.name
+	}
+	if s.fun == nil {
+		return "var " + s.name
+	}
+	var params []string
+	for _, param := range s.fun.Params {
+		params = append(params, param.Value)
+	}
+	return "fun " + s.name + "(" + strings.Join(params, ", ") + ")"
+}
+
+type scope struct {
+	owner    *symbol
+	parent   *scope
+	start    Position
+	end      Position
+	symbols  []*symbol
+	children []*scope
+}
+
+func (s *scope) contains(pos Position) bool {
+	return !before(pos, s.start) && before(pos, s.end)
+}
+
+func (s *scope) lookup(name string, pos Position) *symbol {
+	for sc := s; sc != nil; sc = sc.parent {
+		var found *symbol
+		for _, sym := range sc.symbols {
+			if sym.name != name {
+				continue
+			}
+			if found == nil || before(tokenStart(sym.token), pos) {
+				found = sym
+			}
+		}
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+type reference struct {
+	ident  *parser.Identifier
+	symbol *symbol
+}
+
+type analysis struct {
+	root    *scope
+	symbols []*symbol
+	refs    []*reference
+}
+
+func analyze(pkg *parser.Package) *analysis {
+	a := &analysis{root: &scope{end: Position{Line: 1 << 30}}}
+	var pending []pendingRef
+	current := a.root
+	var walk func(node parser.Node)
+	declare := func(ident *parser.Identifier, kind int, fun *parser.Function) *symbol {
+		sym := &symbol{name: ident.Value, kind: kind, token: ident.Token, fun: fun, scope: current}
+		current.symbols = append(current.symbols, sym)
+		a.symbols = append(a.symbols, sym)
+		a.refs = append(a.refs, &reference{ident: ident, symbol: sym})
+		return sym
+	}
+	enter := func(start, end lexer.Token) {
+		child := &scope{
+			parent: current,
+			start:  tokenStart(start),
+			end:    tokenEnd(end),
+		}
+		current.children = append(current.children, child)
+		current = child
+	}
+	var declarePattern func(pattern parser.Node)
+	declarePattern = func(pattern parser.Node) {
+		switch p := pattern.(type) {
+		case *parser.Identifier:
+			if p.Value != "_" {
+				declare(p, SymbolVariable, nil)
+			}
+		case *parser.ArrayPattern:
+			for _, elem := range p.Elements {
+				declarePattern(elem)
+			}
+		case *parser.MapPattern:
+			for _, value := range p.Values {
+				declarePattern(value)
+			}
+		case *parser.VariantPattern:
+			walk(p.Enum)
+			for _, param := range p.Params {
+				declarePattern(param)
+			}
+		}
+	}
+	walk = func(node parser.Node) {
+		switch n := node.(type) {
+		case *parser.Identifier:
+			if n != nil {
+				pending = append(pending, pendingRef{ident: n, scope: current})
+			}
+		case *parser.Variable:
+			if n != nil && n.Name != nil {
+				declare(n.Name, SymbolVariable, nil)
+			}
+		case *parser.Constant:
+			if n != nil && n.Name != nil {
+				declare(n.Name, SymbolConstant, nil)
+			}
+		case *parser.Struct:
+			if n != nil && n.Name != nil {
+				declare(n.Name, SymbolStruct, nil).def = n
+			}
+		case *parser.Enum:
+			if n != nil && n.Name != nil {
+				declare(n.Name, SymbolEnum, nil).def = n
+			}
+		case *parser.Class:
+			if n == nil || n.Name == nil {
+				return
+			}
+			sym := declare(n.Name, SymbolClass, nil)
+			sym.def = n
+			if n.Parent != nil {
+				walk(n.Parent)
+			}
+			enter(n.Token, n.End)
+			current.owner, sym.body = sym, current
+			for _, method := range n.Methods {
+				walk(method)
+			}
+			current = current.parent
+		case *parser.StructLiteral:
+			if n != nil {
+				walk(n.Name)
+				for _, value := range n.Values {
+					walk(value)
+				}
+			}
+		case *parser.Field:
+			if n != nil {
+				walk(n.Object)
+			}
+		case *parser.Assign:
+			if n != nil {
+				walk(n.Left)
+				walk(n.Right)
+			}
+		case *parser.Update:
+			if n != nil {
+				walk(n.Target)
+				walk(n.Value)
+			}
+		case *parser.Prefix:
+			if n != nil {
+				walk(n.Right)
+			}
+		case *parser.Infix:
+			if n != nil {
+				walk(n.Left)
+				walk(n.Right)
+			}
+		case *parser.FuncCall:
+			if n != nil {
+				walk(n.Function)
+				for _, arg := range n.Arguments {
+					walk(arg)
+				}
+			}
+		case *parser.Return:
+			if n != nil {
+				walk(n.Exp)
+			}
+		case *parser.Yield:
+			if n != nil {
+				walk(n.Value)
+			}
+		case *parser.Block:
+			if n != nil {
+				enter(n.Token, n.End)
+				for _, sub := range n.Nodes {
+					walk(sub)
+				}
+				current = current.parent
+			}
+		case *parser.Conditional:
+			if n != nil {
+				walk(n.Require)
+				walk(n.To)
+				walk(n.Else)
+			}
+		case *parser.Loop:
+			if n != nil {
+				walk(n.Body)
+			}
+		case *parser.While:
+			if n != nil {
+				walk(n.Require)
+				walk(n.Body)
+			}
+		case *parser.For:
+			if n != nil && n.Body != nil {
+				enter(n.Token, n.Body.End)
+				walk(n.Init)
+				walk(n.Require)
+				walk(n.Post)
+				walk(n.Body)
+				current = current.parent
+			}
+		case *parser.Match:
+			if n != nil {
+				walk(n.Value)
+				enter(n.Token, n.End)
+				for _, arm := range n.Arms {
+					for _, pattern := range arm.Patterns {
+						declarePattern(pattern)
+					}
+					walk(arm.Guard)
+					walk(arm.Body)
+				}
+				current = current.parent
+			}
+		case *parser.Labeled:
+			if n != nil {
+				walk(n.Loop)
+			}
+		case *parser.ForIn:
+			if n != nil && n.Body != nil {
+				walk(n.Iterable)
+				enter(n.Token, n.Body.End)
+				if n.Key != nil {
+					declare(n.Key, SymbolVariable, nil)
+				}
+				declare(n.Value, SymbolVariable, nil)
+				walk(n.Body)
+				current = current.parent
+			}
+		case *parser.Function:
+			if n == nil || n.Name == nil {
+				return
+			}
+			sym := declare(n.Name, SymbolFunction, n)
+			if n.Body == nil {
+				return
+			}
+			enter(n.Token, n.Body.End)
+			current.owner, sym.body = sym, current
+			for _, param := range n.Params {
+				declare(param, SymbolVariable, nil)
+			}
+			walk(n.Body)
+			current = current.parent
+		}
+	}
+	for _, node := range pkg.Nodes {
+		walk(node)
+	}
+	// Resolved once every declaration is known, as function
+	// bodies may refer to names declared after them.
+	for _, ref := range pending {
+		sym := ref.scope.lookup(ref.ident.Value, tokenStart(ref.ident.Token))
+		a.refs = append(a.refs, &reference{ident: ref.ident, symbol: sym})
+	}
+	return a
+}
+
+type pendingRef struct {
+	ident *parser.Identifier
+	scope *scope
+}
+
+func (a *analysis) referenceAt(pos Position) *reference {
+	for _, ref := range a.refs {
+		r := identRange(ref.ident)
+		if !before(pos, r.Start) && !before(r.End, pos) {
+			return ref
+		}
+	}
+	return nil
+}
+
+func (a *analysis) scopeAt(pos Position) *scope {
+	current := a.root
+	for {
+		var next *scope
+		for _, child := range current.children {
+			if child.contains(pos) {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return current
+		}
+		current = next
+	}
+}
+
+func (a *analysis) visible(pos Position) []*symbol {
+	var (
+		seen    = make(map[string]bool)
+		symbols []*symbol
+	)
+	for sc := a.scopeAt(pos); sc != nil; sc = sc.parent {
+		for i := len(sc.symbols) - 1; i >= 0; i-- {
+			sym := sc.symbols[i]
+			if seen[sym.name] {
+				continue
+			}
+			seen[sym.name] = true
+			symbols = append(symbols, sym)
+		}
+	}
+	return symbols
+}
+
+func before(a, b Position) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Character < b.Character
+}
+
+func tokenStart(t lexer.Token) Position {
+	return Position{Line: t.Line, Character: t.Column}
+}
+
+func tokenEnd(t lexer.Token) Position {
+	return Position{Line: t.Line, Character: t.Column + len(t.Literal)}
+}
+
+func identRange(ident *parser.Identifier) Range {
+	return Range{Start: tokenStart(ident.Token), End: tokenEnd(ident.Token)}
+}
@@ -0,0 +1,305 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+type document struct {
+	uri      string
+	text     string
+	pkg      *parser.Package
+	analysis *analysis
+}
+
+type Server struct {
+	in       *bufio.Reader
+	out      io.Writer
+	mu       sync.Mutex
+	docs     map[string]*document
+	builtins *object.Builtins
+	shutdown bool
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		in:       bufio.NewReader(in),
+		out:      out,
+		docs:     make(map[string]*document),
+		builtins: object.NewBuiltins(),
+	}
+}
+
+// Serve handles messages until the client sends exit or
+// closes the input stream.
+func (s *Server) Serve() error {
+	for {
+		req, err := s.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+		result, rerr := s.handle(req)
+		if req.ID == nil {
+			continue // Notifications get no response
+		}
+		if err := s.write(&response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rerr}); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) read() (*request, error) {
+	header, err := textproto.NewReader(s.in).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %v", err)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, err
+	}
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (s *Server) write(msg interface{}) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
+
+func (s *Server) handle(req *request) (interface{}, *responseError) {
+	switch req.Method {
+	case "initialize":
+		var result InitializeResult
+		result.ServerInfo.Name = "eldr"
+		result.Capabilities.TextDocumentSync = 1 // Full document sync
+		result.Capabilities.DefinitionProvider = true
+		result.Capabilities.HoverProvider = true
+		result.Capabilities.DocumentSymbolProvider = true
+		result.Capabilities.CompletionProvider.TriggerCharacters = []string{}
+		return result, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return nil, s.update(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params DidChangeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		return nil, s.update(params.TextDocument.URI, text)
+	case "textDocument/didClose":
+		var params DidCloseParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return nil, nil
+	case "textDocument/definition":
+		return s.withPosition(req, s.definition)
+	case "textDocument/hover":
+		return s.withPosition(req, s.hover)
+	case "textDocument/completion":
+		return s.withPosition(req, s.completion)
+	case "textDocument/documentSymbol":
+		var params struct {
+			TextDocument TextDocumentIdentifier `json:"textDocument"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		doc, ok := s.docs[params.TextDocument.URI]
+		if !ok {
+			return []DocumentSymbol{}, nil
+		}
+		return documentSymbols(doc.analysis.root), nil
+	}
+	if req.ID == nil {
+		return nil, nil
+	}
+	return nil, &responseError{Code: -32601, Message: "method not found: " + req.Method}
+}
+
+func (s *Server) withPosition(req *request, fn func(*document, Position) interface{}) (interface{}, *responseError) {
+	var params PositionParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, invalidParams(err)
+	}
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+	return fn(doc, params.Position), nil
+}
+
+func (s *Server) update(uri, text string) *responseError {
+	doc := &document{uri: uri, text: text}
+	doc.pkg = parser.NewParser().ParsePackage(text, "main")
+	doc.analysis = analyze(doc.pkg)
+	s.docs[uri] = doc
+
+	diagnostics := []Diagnostic{}
+	for _, e := range doc.pkg.Errors {
+		token := e.Token()
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    Range{Start: tokenStart(token), End: tokenEnd(token)},
+			Severity: SeverityError,
+			Source:   "eldr",
+			Message:  e.Message(),
+		})
+	}
+	for _, w := range doc.pkg.Warnings {
+		token := w.Token()
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    Range{Start: tokenStart(token), End: tokenEnd(token)},
+			Severity: SeverityWarning,
+			Source:   "eldr",
+			Message:  w.Message(),
+		})
+	}
+	err := s.write(&notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  PublishDiagnosticsParams{URI: uri, Diagnostics: diagnostics},
+	})
+	if err != nil {
+		return &responseError{Code: -32603, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) definition(doc *document, pos Position) interface{} {
+	ref := doc.analysis.referenceAt(pos)
+	if ref == nil || ref.symbol == nil {
+		return nil
+	}
+	return Location{
+		URI:   doc.uri,
+		Range: Range{Start: tokenStart(ref.symbol.token), End: tokenEnd(ref.symbol.token)},
+	}
+}
+
+func (s *Server) hover(doc *document, pos Position) interface{} {
+	ref := doc.analysis.referenceAt(pos)
+	if ref == nil {
+		return nil
+	}
+	var value string
+	switch builtin := s.builtins.Get(ref.ident.Value); {
+	case ref.symbol != nil:
+		value = "```eldr\n" + ref.symbol.signature() + "\n```"
+	case builtin != nil:
+		value = "```eldr\nbuiltin " + builtin.Signature() + "\n```\n" + builtin.Doc
+	default:
+		return nil
+	}
+	r := identRange(ref.ident)
+	return Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: value},
+		Range:    &r,
+	}
+}
+
+func (s *Server) completion(doc *document, pos Position) interface{} {
+	items := []CompletionItem{}
+	for _, sym := range doc.analysis.visible(pos) {
+		kind := CompletionVariable
+		switch sym.kind {
+		case SymbolFunction:
+			kind = CompletionFunction
+		case SymbolConstant:
+			kind = CompletionConstant
+		case SymbolStruct:
+			kind = CompletionStruct
+		case SymbolClass:
+			kind = CompletionClass
+		case SymbolEnum:
+			kind = CompletionEnum
+		}
+		items = append(items, CompletionItem{Label: sym.name, Kind: kind, Detail: sym.signature()})
+	}
+	for _, name := range s.builtins.Names() {
+		items = append(items, CompletionItem{
+			Label:  name,
+			Kind:   CompletionFunction,
+			Detail: "builtin " + s.builtins.Get(name).Signature(),
+		})
+	}
+	for _, keyword := range lexer.Keywords() {
+		items = append(items, CompletionItem{Label: keyword, Kind: CompletionKeyword})
+	}
+	return items
+}
+
+func documentSymbols(sc *scope) []DocumentSymbol {
+	symbols := []DocumentSymbol{}
+	// Function parameters are not listed, methods are
+	if sc.owner == nil || sc.owner.kind == SymbolClass {
+		for _, sym := range sc.symbols {
+			symbols = append(symbols, documentSymbol(sym))
+		}
+	}
+	for _, child := range sc.children {
+		if child.owner == nil {
+			symbols = append(symbols, documentSymbols(child)...)
+		}
+	}
+	return symbols
+}
+
+func documentSymbol(sym *symbol) DocumentSymbol {
+	selection := Range{Start: tokenStart(sym.token), End: tokenEnd(sym.token)}
+	ds := DocumentSymbol{
+		Name:           sym.name,
+		Detail:         sym.signature(),
+		Kind:           sym.kind,
+		Range:          selection,
+		SelectionRange: selection,
+	}
+	if sym.body != nil {
+		ds.Range = Range{Start: sym.body.start, End: sym.body.end}
+		ds.Children = documentSymbols(sym.body)
+	}
+	return ds
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: -32602, Message: err.Error()}
+}
@@ -0,0 +1,241 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type client struct {
+	t      *testing.T
+	in     io.Writer
+	out    *bufio.Reader
+	nextID int
+}
+
+func newClient(t *testing.T) (*client, chan error) {
+	cin, sin := io.Pipe()
+	sout, cout := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(cin, cout).Serve()
+		_ = cout.Close()
+	}()
+	return &client{t: t, in: sin, out: bufio.NewReader(sout)}, done
+}
+
+func (c *client) send(id int, method string, params interface{}) {
+	msg := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+	if id > 0 {
+		msg["id"] = id
+	}
+	body, _ := json.Marshal(msg)
+	_, err := fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *client) receive() map[string]json.RawMessage {
+	header, err := textproto.NewReader(c.out).ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	length, _ := strconv.Atoi(header.Get("Content-Length"))
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.out, body); err != nil {
+		c.t.Fatal(err)
+	}
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.t.Fatal(err)
+	}
+	return msg
+}
+
+func (c *client) call(method string, params interface{}, result interface{}) {
+	c.nextID++
+	c.send(c.nextID, method, params)
+	msg := c.receive()
+	if msg["error"] != nil {
+		c.t.Fatalf("%s failed: %s", method, msg["error"])
+	}
+	if err := json.Unmarshal(msg["result"], result); err != nil {
+		c.t.Fatalf("%s bad result %s: %v", method, msg["result"], err)
+	}
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.send(0, method, params)
+}
+
+func position(uri string, line, char int) interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]string{"uri": uri},
+		"position":     Position{Line: line, Character: char},
+	}
+}
+
+const source = `var count = 1;
+fun add(a, b) {
+	var sum = a + b;
+	return sum;
+}
+add(count, 2);
+`
+
+func TestServerSession(t *testing.T) {
+	c, done := newClient(t)
+	uri := "file:///test.eld"
+
+	var init InitializeResult
+	c.call("initialize", map[string]interface{}{}, &init)
+	if !init.Capabilities.DefinitionProvider || init.Capabilities.TextDocumentSync != 1 {
+		t.Fatalf("unexpected capabilities %+v", init.Capabilities)
+	}
+	c.notify("initialized", map[string]interface{}{})
+
+	// Diagnostics for a broken document, then a fixed one
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": TextDocumentItem{URI: uri, Text: "var a = 1;\nif a { }\n"},
+	})
+	var diags PublishDiagnosticsParams
+	msg := c.receive()
+	_ = json.Unmarshal(msg["params"], &diags)
+	if len(diags.Diagnostics) == 0 || diags.Diagnostics[0].Range.Start.Line != 1 {
+		t.Fatalf("expected diagnostic on line 1, got %+v", diags)
+	}
+	c.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   TextDocumentIdentifier{URI: uri},
+		"contentChanges": []map[string]string{{"text": source}},
+	})
+	msg = c.receive()
+	_ = json.Unmarshal(msg["params"], &diags)
+	if len(diags.Diagnostics) != 0 {
+		t.Fatalf("expected no diagnostics, got %+v", diags.Diagnostics)
+	}
+
+	// Definition of "sum" in return, "count" and "add" in the call
+	var loc Location
+	c.call("textDocument/definition", position(uri, 3, 9), &loc)
+	if loc.Range.Start != (Position{Line: 2, Character: 5}) {
+		t.Fatalf("wrong sum definition %+v", loc)
+	}
+	c.call("textDocument/definition", position(uri, 5, 5), &loc)
+	if loc.Range.Start != (Position{Line: 0, Character: 4}) {
+		t.Fatalf("wrong count definition %+v", loc)
+	}
+	c.call("textDocument/definition", position(uri, 5, 1), &loc)
+	if loc.Range.Start != (Position{Line: 1, Character: 4}) {
+		t.Fatalf("wrong add definition %+v", loc)
+	}
+
+	// Hover over user function and builtin
+	var hover Hover
+	c.call("textDocument/hover", position(uri, 5, 0), &hover)
+	if !strings.Contains(hover.Contents.Value, "fun add(a, b)") {
+		t.Fatalf("wrong hover %q", hover.Contents.Value)
+	}
+	c.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   TextDocumentIdentifier{URI: uri},
+		"contentChanges": []map[string]string{{"text": source + "len(\"abc\");\n"}},
+	})
+	c.receive()
+	c.call("textDocument/hover", position(uri, 6, 1), &hover)
+	if !strings.Contains(hover.Contents.Value, "builtin len(value: string|array|map)") {
+		t.Fatalf("wrong builtin hover %q", hover.Contents.Value)
+	}
+
+	// Document symbols
+	var symbols []DocumentSymbol
+	c.call("textDocument/documentSymbol", map[string]interface{}{
+		"textDocument": TextDocumentIdentifier{URI: uri},
+	}, &symbols)
+	if len(symbols) != 2 || symbols[1].Name != "add" || symbols[1].Kind != SymbolFunction {
+		t.Fatalf("wrong symbols %+v", symbols)
+	}
+	if len(symbols[1].Children) != 1 || symbols[1].Children[0].Name != "sum" {
+		t.Fatalf("wrong function children %+v", symbols[1].Children)
+	}
+
+	// Completion inside the function body sees params and locals
+	var items []CompletionItem
+	c.call("textDocument/completion", position(uri, 3, 1), &items)
+	labels := make(map[string]bool)
+	for _, item := range items {
+		labels[item.Label] = true
+	}
+	for _, name := range []string{"a", "b", "sum", "add", "count", "print", "var"} {
+		if !labels[name] {
+			t.Fatalf("missing completion %q in %v", name, labels)
+		}
+	}
+	c.call("textDocument/completion", position(uri, 5, 0), &items)
+	for _, item := range items {
+		if item.Label == "sum" {
+			t.Fatal("function local visible outside its body")
+		}
+	}
+
+	var null interface{}
+	c.call("shutdown", nil, &null)
+	c.notify("exit", nil)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnterminatedLoops(t *testing.T) {
+	c, done := newClient(t)
+	var init InitializeResult
+	c.call("initialize", map[string]interface{}{}, &init)
+	for i, text := range []string{
+		"loop {",
+		"while (true) {",
+		"for (;;) {",
+		"var i = 0;\nfor (var i = 0; i < 3; i++) {\n\ti;",
+		"for x in xs {",
+		"for k, v in range(0, 3) {\n\tk;",
+	} {
+		uri := fmt.Sprintf("file:///loop%d.eld", i)
+		c.notify("textDocument/didOpen", map[string]interface{}{
+			"textDocument": TextDocumentItem{URI: uri, Text: text},
+		})
+		var diags PublishDiagnosticsParams
+		_ = json.Unmarshal(c.receive()["params"], &diags)
+		if len(diags.Diagnostics) == 0 {
+			t.Fatalf("%q expected a diagnostic", text)
+		}
+	}
+	var null interface{}
+	c.call("shutdown", nil, &null)
+	c.notify("exit", nil)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCoverageWarnings(t *testing.T) {
+	c, done := newClient(t)
+	var init InitializeResult
+	c.call("initialize", map[string]interface{}{}, &init)
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": TextDocumentItem{URI: "file:///enum.eld", Text: "enum Color { Red, Blue }\nmatch (c) { Color.Red => 1 }\n"},
+	})
+	var diags PublishDiagnosticsParams
+	_ = json.Unmarshal(c.receive()["params"], &diags)
+	if len(diags.Diagnostics) != 1 || diags.Diagnostics[0].Severity != SeverityWarning || diags.Diagnostics[0].Range.Start.Line != 1 {
+		t.Fatalf("expected a warning on line 1, got %+v", diags.Diagnostics)
+	}
+	var null interface{}
+	c.call("shutdown", nil, &null)
+	c.notify("exit", nil)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,14 +2,178 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"github.com/Onelio/Eldrlang/dap"
+	"github.com/Onelio/Eldrlang/debugger"
 	"github.com/Onelio/Eldrlang/evaluator"
+	"github.com/Onelio/Eldrlang/lsp"
+	"github.com/Onelio/Eldrlang/object"
 	"github.com/Onelio/Eldrlang/parser"
+	"github.com/Onelio/Eldrlang/tester"
+	"io/ioutil"
+	"net"
 	"os"
 	"strings"
+	"time"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "lsp":
+			if err := lsp.NewServer(os.Stdin, os.Stdout).Serve(); err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		case "debug":
+			debug(os.Args[2:])
+			return
+		case "test":
+			os.Exit(test(os.Args[2:]))
+		case "dap":
+			if err := serveDAP(os.Args[2:]); err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	flags := flag.NewFlagSet("eldr", flag.ExitOnError)
+	sandbox := newSandboxFlags(flags)
+	_ = flags.Parse(os.Args[1:])
+	if flags.NArg() > 0 {
+		run(flags.Arg(0), sandbox.policy(flags))
+		return
+	}
+	console(sandbox.policy(flags))
+}
+
+func load(path string) (string, *parser.Package) {
+	code, err := ioutil.ReadFile(path)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	parsed := parser.NewParser().ParsePackage(string(code), "main")
+	if parsed.Errors.Len() > 0 {
+		fmt.Print(parsed.Errors.String())
+		os.Exit(1)
+	}
+	_, _ = fmt.Fprint(os.Stderr, parsed.Warnings.String())
+	return string(code), parsed
+}
+
+func run(path string, policy *object.Policy) {
+	_, parsed := load(path)
+	eval := evaluator.NewEvaluator()
+	eval.Policy = policy
+	obj := eval.Evaluate(parsed)
+	eval.Close()
+	if obj.Errors.Len() > 0 {
+		fmt.Print(obj.Errors.String())
+		os.Exit(1)
+	}
+}
+
+func debug(args []string) {
+	flags := flag.NewFlagSet("debug", flag.ExitOnError)
+	sandbox := newSandboxFlags(flags)
+	_ = flags.Parse(args)
+	if flags.NArg() < 1 {
+		_, _ = fmt.Fprintln(os.Stderr, "usage: eldr debug [flags] script.eld")
+		os.Exit(2)
+	}
+	code, parsed := load(flags.Arg(0))
+	var (
+		eval  = evaluator.NewEvaluator()
+		front = debugger.NewConsole(os.Stdin, os.Stdout, code)
+	)
+	eval.Policy = sandbox.policy(flags)
+	obj, err := debugger.NewDebugger(eval, front).Run(parsed, true)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if obj.Errors.Len() > 0 {
+		fmt.Print(obj.Errors.String())
+	}
+}
+
+func test(args []string) int {
+	flags := flag.NewFlagSet("test", flag.ExitOnError)
+	sandbox := newSandboxFlags(flags)
+	verbose := flags.Bool("v", false, "report every test instead of only the failed ones")
+	junit := flags.String("junit", "", "write a JUnit XML report to the given file")
+	timeout := flags.Duration("timeout", 10*time.Second, "stop every test after the given time")
+	_ = flags.Parse(args)
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := tester.Discover(paths...)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(files) == 0 {
+		fmt.Println("no test files")
+		return 0
+	}
+	var (
+		opts   = tester.Options{Timeout: *timeout, Policy: sandbox.policy(flags)}
+		suites []*tester.Suite
+		status = 0
+	)
+	for _, file := range files {
+		suite, err := tester.RunFile(file, opts)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		tester.Report(os.Stdout, suite, *verbose)
+		if !suite.Passed() {
+			status = 1
+		}
+		suites = append(suites, suite)
+	}
+	if *junit != "" {
+		report, err := os.Create(*junit)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer report.Close()
+		if err := tester.WriteJUnit(report, suites); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	return status
+}
+
+func serveDAP(args []string) error {
+	flags := flag.NewFlagSet("dap", flag.ExitOnError)
+	port := flags.Int("port", 0, "serve on a localhost TCP port instead of stdio")
+	_ = flags.Parse(args)
+	if *port != 0 {
+		listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", *port))
+		if err != nil {
+			return err
+		}
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		return dap.NewServer(conn, conn).Serve()
+	}
+	return dap.NewServer(os.Stdin, os.Stdout).Serve()
+}
+
+func console(policy *object.Policy) {
 	fmt.Println(LOGO)
 	var (
 		input = bufio.NewReader(os.Stdin)
@@ -17,9 +181,14 @@ func main() {
 		eval  = evaluator.NewEvaluator()
 		code  = ""
 	)
+	eval.Policy = policy
+	defer eval.Close()
 	for {
 		fmt.Print(">>")
-		line, _ := input.ReadString('\n')
+		line, err := input.ReadString('\n')
+		if err != nil && line == "" {
+			return
+		}
 		// Special commands check
 		if strings.HasPrefix(line, "exit") {
 			return
@@ -39,6 +208,7 @@ func main() {
 			fmt.Print(parsed.Errors.String())
 			continue
 		}
+		fmt.Print(parsed.Warnings.String())
 
 		obj := eval.Evaluate(parsed)
 		if obj.Errors.Len() > 0 {
@@ -0,0 +1,35 @@
+package object
+
+import (
+	"math/big"
+)
+
+// BigInt holds the integers out of the int64 range. Arithmetic
+// promotes to it on overflow, and results back in range are
+// demoted to Integer, so it is an integer to scripts.
+type BigInt struct {
+	Value *big.Int
+}
+
+func (b *BigInt) Type() Type      { return INTEGER }
+func (b *BigInt) Inspect() string { return b.Value.String() }
+
+// FromBig returns an Integer when the value fits in one, and
+// a BigInt otherwise.
+func FromBig(value *big.Int) Object {
+	if value.IsInt64() {
+		return &Integer{Value: value.Int64()}
+	}
+	return &BigInt{Value: value}
+}
+
+// ToBig returns the value of an Integer or a BigInt.
+func ToBig(obj Object) (*big.Int, bool) {
+	switch o := obj.(type) {
+	case *Integer:
+		return big.NewInt(o.Value), true
+	case *BigInt:
+		return o.Value, true
+	}
+	return nil, false
+}
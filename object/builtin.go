@@ -2,99 +2,316 @@ package object
 
 import (
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
+	"strings"
 )
 
-type BuiltFun func(args ...Object) Object
-
-var Builtins = map[string]*Builtin{
-	"len":    {Size: 1, Fun: builtLen},
-	"print":  {Size: -1, Fun: builtPrint},
-	"scan":   {Size: 1, Fun: builtScan},
-	"fopen":  {Size: 1, Fun: builtFOpen},
-	"fclose": {Size: 1, Fun: builtFClose},
-	"fread":  {Size: 1, Fun: builtFRead},
-	"fwrite": {Size: 2, Fun: builtFWrite},
+func defaultBuiltins() []*Builtin {
+	return []*Builtin{
+		{
+			Name:   "len",
+			Params: []Param{{Name: "value", Types: []Type{STRING, ARRAY, MAP}}},
+			Doc:    "Returns the length of a string, array or map.",
+			Fun:    builtLen,
+		},
+		{
+			Name: "range",
+			Params: []Param{
+				{Name: "start", Types: []Type{INTEGER}},
+				{Name: "end", Types: []Type{INTEGER}},
+				{Name: "step", Types: []Type{INTEGER}, Optional: true},
+			},
+			Doc: "Returns the integers from start up to end, excluded, moving by step (1 by default) as they are iterated.",
+			Fun: builtRange,
+		},
+		{
+			Name:   "next",
+			Params: []Param{{Name: "generator", Types: []Type{GENERATOR}}},
+			Doc:    "Resumes a generator until its next value, returned, or null once it returned.",
+			Fun:    builtNext,
+		},
+		{
+			Name:   "print",
+			Params: []Param{{Name: "values", Variadic: true}},
+			Doc:    "Writes the values to the standard output.",
+			Fun:    builtPrint,
+		},
+		{
+			Name:   "println",
+			Params: []Param{{Name: "values", Variadic: true}},
+			Doc:    "Writes the values and a line break to the standard output.",
+			Fun:    builtPrintln,
+		},
+		{
+			Name:   "eprint",
+			Params: []Param{{Name: "values", Variadic: true}},
+			Doc:    "Writes the values to the standard error.",
+			Fun:    builtEPrint,
+		},
+		{
+			Name:   "input",
+			Params: []Param{{Name: "prompt", Types: []Type{STRING}, Optional: true}},
+			Doc:    "Writes the prompt to the standard output and returns a line read from the standard input.",
+			Fun:    builtInput,
+		},
+		{
+			Name:   "scan",
+			Params: []Param{{Name: "target", Types: []Type{STRING, INTEGER, BOOLEAN}}},
+			Doc:    "Reads a line from the standard input into the target variable.",
+			Fun:    builtScan,
+		},
+		{
+			Name: "fopen",
+			Params: []Param{
+				{Name: "path", Types: []Type{STRING}},
+				{Name: "mode", Types: []Type{STRING}, Optional: true},
+			},
+			Doc: "Opens a file in mode r (default), w, a or rw.",
+			Fun: builtFOpen,
+		},
+		{
+			Name:   "fclose",
+			Params: []Param{{Name: "file", Types: []Type{FILE}}},
+			Doc:    "Closes a file.",
+			Fun:    builtFClose,
+		},
+		{
+			Name:   "fread",
+			Params: []Param{{Name: "file", Types: []Type{FILE}}},
+			Doc:    "Reads the rest of a file.",
+			Fun:    builtFRead,
+		},
+		{
+			Name:   "freadline",
+			Params: []Param{{Name: "file", Types: []Type{FILE}}},
+			Doc:    "Reads the next line of a file with its line break, an empty string at the end.",
+			Fun:    builtFReadLine,
+		},
+		{
+			Name: "fwrite",
+			Params: []Param{
+				{Name: "file", Types: []Type{FILE}},
+				{Name: "data", Types: []Type{STRING}},
+			},
+			Doc: "Writes a string to a file and returns the amount of bytes written.",
+			Fun: builtFWrite,
+		},
+		{
+			Name: "fseek",
+			Params: []Param{
+				{Name: "file", Types: []Type{FILE}},
+				{Name: "offset", Types: []Type{INTEGER}},
+				{Name: "whence", Types: []Type{INTEGER}, Optional: true},
+			},
+			Doc: "Moves within a file relative to the start (0, default), current position (1) or end (2).",
+			Fun: builtFSeek,
+		},
+		{
+			Name:   "fexists",
+			Params: []Param{{Name: "path", Types: []Type{STRING}}},
+			Doc:    "Tells whether a file exists.",
+			Fun:    builtFExists,
+		},
+		{
+			Name:   "fremove",
+			Params: []Param{{Name: "path", Types: []Type{STRING}}},
+			Doc:    "Removes a file.",
+			Fun:    builtFRemove,
+		},
+	}
 }
 
-func builtLen(args ...Object) Object {
+func builtLen(rt *Runtime, args ...Object) (Object, error) {
 	switch arg := args[0].(type) {
 	case *String:
-		return &Integer{Value: int64(len(arg.Value))}
-	default:
-		return nil
+		return &Integer{Value: int64(len(arg.Value))}, nil
+	case *Array:
+		return &Integer{Value: int64(len(arg.Elements))}, nil
+	case *Map:
+		return &Integer{Value: int64(arg.Len())}, nil
 	}
+	return nil, nil
 }
 
-func builtPrint(args ...Object) Object {
-	for _, arg := range args {
-		switch elem := arg.(type) {
-		case *String:
-			fmt.Print(elem.Value)
-		case *Integer:
-			fmt.Print(elem.Value)
-		case *Boolean:
-			fmt.Print(elem.Value)
+func builtRange(rt *Runtime, args ...Object) (Object, error) {
+	r := &Range{Step: 1}
+	bounds := []*int64{&r.Start, &r.End, &r.Step}
+	for i, arg := range args {
+		value, err := int64Arg(arg)
+		if err != nil {
+			return nil, err
 		}
+		*bounds[i] = value
+	}
+	if r.Step == 0 {
+		return nil, fmt.Errorf("range step must not be zero")
+	}
+	return r, nil
+}
+
+func builtNext(rt *Runtime, args ...Object) (Object, error) {
+	if value, ok := args[0].(*Generator).Next(); ok {
+		return value, nil
 	}
-	return nil
+	return &Null{}, nil
 }
 
-func builtScan(args ...Object) Object {
+func builtPrint(rt *Runtime, args ...Object) (Object, error) {
+	if err := rt.Policy.CheckStdout(); err != nil {
+		return nil, err
+	}
+	return nil, write(rt.Stdout, args, "")
+}
+
+func builtPrintln(rt *Runtime, args ...Object) (Object, error) {
+	if err := rt.Policy.CheckStdout(); err != nil {
+		return nil, err
+	}
+	return nil, write(rt.Stdout, args, "\n")
+}
+
+func builtEPrint(rt *Runtime, args ...Object) (Object, error) {
+	if err := rt.Policy.CheckStderr(); err != nil {
+		return nil, err
+	}
+	return nil, write(rt.Stderr, args, "")
+}
+
+func builtInput(rt *Runtime, args ...Object) (Object, error) {
+	if len(args) > 0 {
+		if err := rt.Policy.CheckStdout(); err != nil {
+			return nil, err
+		}
+		if err := write(rt.Stdout, args, ""); err != nil {
+			return nil, err
+		}
+	}
+	if err := rt.Policy.CheckStdin(); err != nil {
+		return nil, err
+	}
+	line, err := rt.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	return &String{Value: line}, nil
+}
+
+func builtScan(rt *Runtime, args ...Object) (Object, error) {
+	if err := rt.Policy.CheckStdin(); err != nil {
+		return nil, err
+	}
+	line, err := rt.ReadLine()
+	if err != nil {
+		return nil, err
+	}
 	switch arg := args[0].(type) {
 	case *String:
-		_, _ = fmt.Scanln(&arg.Value)
+		arg.Value = line
 	case *Integer:
-		_, _ = fmt.Scanln(&arg.Value)
+		_, err = fmt.Sscan(line, &arg.Value)
 	case *Boolean:
-		_, _ = fmt.Scanln(&arg.Value)
+		_, err = fmt.Sscan(line, &arg.Value)
 	}
-	return nil
+	return nil, err
 }
 
-func builtFOpen(args ...Object) Object {
-	switch arg := args[0].(type) {
-	case *String:
-		file, err := os.Open(arg.Value)
-		if err != nil {
-			return &Integer{Value: 0}
+func int64Arg(arg Object) (int64, error) {
+	if integer, ok := arg.(*Integer); ok {
+		return integer.Value, nil
+	}
+	return 0, fmt.Errorf("integer %s out of range", arg.Inspect())
+}
+
+func write(out io.Writer, args []Object, end string) error {
+	var text strings.Builder
+	for _, arg := range args {
+		if str, ok := arg.(*String); ok {
+			text.WriteString(str.Value)
+		} else {
+			text.WriteString(inspect(arg))
 		}
-		return &Integer{Value: int64(file.Fd())}
-	default:
-		return nil
 	}
+	text.WriteString(end)
+	_, err := io.WriteString(out, text.String())
+	return err
 }
 
-func builtFClose(args ...Object) Object {
-	switch arg := args[0].(type) {
-	case *Integer:
-		file := os.NewFile(uintptr(arg.Value), "pipe")
-		_ = file.Close()
+func builtFOpen(rt *Runtime, args ...Object) (Object, error) {
+	mode := "r"
+	if len(args) > 1 {
+		mode = args[1].(*String).Value
 	}
-	return nil
+	return rt.OpenFile(args[0].(*String).Value, mode)
 }
 
-func builtFRead(args ...Object) Object {
-	switch arg := args[0].(type) {
-	case *Integer:
-		file := os.NewFile(uintptr(arg.Value), "pipe")
-		data, err := ioutil.ReadAll(file)
-		if err == nil {
-			return &String{Value: string(data)}
-		}
+func builtFClose(rt *Runtime, args ...Object) (Object, error) {
+	file := args[0].(*File)
+	rt.untrack(file)
+	return nil, file.Close()
+}
+
+func builtFRead(rt *Runtime, args ...Object) (Object, error) {
+	data, err := args[0].(*File).ReadAll()
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return &String{Value: data}, nil
 }
 
-func builtFWrite(args ...Object) Object {
-	switch arg := args[0].(type) {
-	case *Integer:
-		file := os.NewFile(uintptr(arg.Value), "pipe")
-		switch arg1 := args[1].(type) {
-		case *String:
-			_, _ = file.Write([]byte(arg1.Value))
+func builtFReadLine(rt *Runtime, args ...Object) (Object, error) {
+	line, err := args[0].(*File).ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	return &String{Value: line}, nil
+}
+
+func builtFWrite(rt *Runtime, args ...Object) (Object, error) {
+	written, err := args[0].(*File).Write(args[1].(*String).Value)
+	if err != nil {
+		return nil, err
+	}
+	return &Integer{Value: int64(written)}, nil
+}
+
+func builtFSeek(rt *Runtime, args ...Object) (Object, error) {
+	offset, err := int64Arg(args[1])
+	if err != nil {
+		return nil, err
+	}
+	whence := int64(0)
+	if len(args) > 2 {
+		if whence, err = int64Arg(args[2]); err != nil {
+			return nil, err
 		}
 	}
-	return nil
+	if whence < 0 || whence > 2 {
+		return nil, fmt.Errorf("invalid whence %s, expected 0, 1 or 2", args[2].Inspect())
+	}
+	pos, err := args[0].(*File).Seek(offset, int(whence))
+	if err != nil {
+		return nil, err
+	}
+	return &Integer{Value: pos}, nil
+}
+
+func builtFExists(rt *Runtime, args ...Object) (Object, error) {
+	path := args[0].(*String).Value
+	if err := rt.Policy.CheckRead(path); err != nil {
+		return nil, err
+	}
+	_, err := os.Stat(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return &Boolean{Value: err == nil}, nil
+}
+
+func builtFRemove(rt *Runtime, args ...Object) (Object, error) {
+	path := args[0].(*String).Value
+	if err := rt.Policy.CheckWrite(path); err != nil {
+		return nil, err
+	}
+	return nil, os.Remove(path)
 }
@@ -0,0 +1,105 @@
+package object
+
+import (
+	"strings"
+)
+
+// Class holds the methods of its instances. Lookups walk up
+// the parents once per method name, then are kept in cache.
+type Class struct {
+	Name    string
+	Parent  *Class
+	Methods map[string]*Function
+	cache   map[string]*Method
+}
+
+func NewClass(name string, parent *Class) *Class {
+	return &Class{
+		Name:    name,
+		Parent:  parent,
+		Methods: make(map[string]*Function),
+		cache:   make(map[string]*Method),
+	}
+}
+
+func (c *Class) Type() Type      { return CLASS }
+func (c *Class) Inspect() string { return "class " + c.Name }
+
+// Lookup finds a method of the class or its closest ancestor
+// declaring it, unbound, or nil if none does.
+func (c *Class) Lookup(name string) *Method {
+	if method, cached := c.cache[name]; cached {
+		return method
+	}
+	var method *Method
+	for owner := c; owner != nil; owner = owner.Parent {
+		if fun, ok := owner.Methods[name]; ok {
+			method = &Method{Fun: fun, Owner: owner}
+			break
+		}
+	}
+	c.cache[name] = method
+	return method
+}
+
+// Method is a function of a class, bound to the instance Self
+// once read from it. Owner is the class declaring it.
+type Method struct {
+	Self  *Instance
+	Fun   *Function
+	Owner *Class
+}
+
+func (m *Method) Type() Type      { return FUNCTION }
+func (m *Method) Inspect() string { return "method " + m.Fun.Name }
+
+func (m *Method) Bind(self *Instance) *Method {
+	return &Method{Self: self, Fun: m.Fun, Owner: m.Owner}
+}
+
+// Instance keeps its fields in the order they were first set.
+type Instance struct {
+	Class  *Class
+	fields map[string]Object
+	order  []string
+}
+
+func NewInstance(class *Class) *Instance {
+	return &Instance{Class: class, fields: make(map[string]Object)}
+}
+
+func (i *Instance) Type() Type { return INSTANCE }
+func (i *Instance) Inspect() string {
+	var fields []string
+	for _, name := range i.order {
+		fields = append(fields, name+": "+inspect(i.fields[name]))
+	}
+	return i.Class.Name + "{" + strings.Join(fields, ", ") + "}"
+}
+
+func (i *Instance) Name() string {
+	return i.Class.Name
+}
+
+// Get returns the value of a field, or else the method named
+// bound to the instance.
+func (i *Instance) Get(field string) Object {
+	if value, ok := i.fields[field]; ok {
+		return value
+	}
+	if method := i.Class.Lookup(field); method != nil {
+		return method.Bind(i)
+	}
+	return nil
+}
+
+func (i *Instance) Set(field string, value Object) bool {
+	if value == nil {
+		value = &Null{}
+	}
+	if _, ok := i.fields[field]; !ok {
+		i.order = append(i.order, field)
+	}
+	i.fields[field] = value
+	return true
+}
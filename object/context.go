@@ -1,13 +1,18 @@
 package object
 
+import (
+	"sort"
+)
+
 type Context struct {
 	store  map[string]Object
+	consts map[string]bool
 	parent *Context
 }
 
 func NewContext() *Context {
 	s := make(map[string]Object)
-	return &Context{store: s}
+	return &Context{store: s, consts: make(map[string]bool)}
 }
 
 func (e *Context) Get(name string) Object {
@@ -18,3 +23,27 @@ func (e *Context) Get(name string) Object {
 func (e *Context) Set(name string, val Object) {
 	e.store[name] = val
 }
+
+// SetConstant binds name like Set, marking it as immutable
+// for the code evaluated. Set keeps the mark when updating it.
+func (e *Context) SetConstant(name string, val Object) {
+	e.store[name] = val
+	e.consts[name] = true
+}
+
+func (e *Context) IsConstant(name string) bool {
+	return e.consts[name]
+}
+
+func (e *Context) Parent() *Context {
+	return e.parent
+}
+
+func (e *Context) Names() []string {
+	var names []string
+	for name := range e.store {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
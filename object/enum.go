@@ -0,0 +1,80 @@
+package object
+
+import (
+	"strings"
+)
+
+// Enum is the type of a set of variants, whose values compare
+// by identity of the variant and then by payload.
+type Enum struct {
+	Name     string
+	Variants []*Variant
+}
+
+func (e *Enum) Type() Type      { return TYPE }
+func (e *Enum) Inspect() string { return "enum " + e.Name }
+
+// AddVariant adds a variant of values carrying one for each
+// param if payload, or else being the only value of it.
+func (e *Enum) AddVariant(name string, params []string, payload bool) {
+	variant := &Variant{Enum: e, Name: name, Params: params, Payload: payload}
+	if !payload {
+		variant.unit = &EnumValue{Variant: variant}
+	}
+	e.Variants = append(e.Variants, variant)
+}
+
+func (e *Enum) Variant(name string) *Variant {
+	for _, variant := range e.Variants {
+		if variant.Name == name {
+			return variant
+		}
+	}
+	return nil
+}
+
+// Value returns the value of a variant, or its constructor
+// when it carries a payload.
+func (e *Enum) Value(name string) Object {
+	variant := e.Variant(name)
+	switch {
+	case variant == nil:
+		return nil
+	case variant.Payload:
+		return variant
+	}
+	return variant.unit
+}
+
+type Variant struct {
+	Enum    *Enum
+	Name    string
+	Params  []string
+	Payload bool
+	unit    *EnumValue
+}
+
+func (v *Variant) Type() Type      { return FUNCTION }
+func (v *Variant) Inspect() string { return v.Enum.Name + "." + v.Name }
+
+func (v *Variant) New(values []Object) *EnumValue {
+	return &EnumValue{Variant: v, Values: values}
+}
+
+type EnumValue struct {
+	Variant *Variant
+	Values  []Object
+}
+
+func (e *EnumValue) Type() Type { return ENUM }
+func (e *EnumValue) Inspect() string {
+	name := e.Variant.Inspect()
+	if !e.Variant.Payload {
+		return name
+	}
+	var values []string
+	for _, value := range e.Values {
+		values = append(values, inspect(value))
+	}
+	return name + "(" + strings.Join(values, ", ") + ")"
+}
@@ -0,0 +1,168 @@
+package object
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+var fileModes = map[string]int{
+	"r":  os.O_RDONLY,
+	"w":  os.O_WRONLY | os.O_CREATE | os.O_TRUNC,
+	"a":  os.O_WRONLY | os.O_CREATE | os.O_APPEND,
+	"rw": os.O_RDWR | os.O_CREATE,
+}
+
+var errClosed = errors.New("file already closed")
+
+// File is an open file owned by the runtime that opened it.
+// Reads are buffered so lines can be read one at a time.
+type File struct {
+	Path   string
+	Mode   string
+	file   *os.File
+	reader *bufio.Reader
+}
+
+func (f *File) Type() Type { return FILE }
+func (f *File) Inspect() string {
+	return fmt.Sprintf("file(%q, %q)", f.Path, f.Mode)
+}
+
+func (f *File) Closed() bool {
+	return f.file == nil
+}
+
+func (f *File) Readable() bool {
+	return f.Mode == "r" || f.Mode == "rw"
+}
+
+func (f *File) Writable() bool {
+	return f.Mode != "r"
+}
+
+// ReadLine returns the next line with its line break, or an
+// empty string at the end of the file.
+func (f *File) ReadLine() (string, error) {
+	if err := f.check(f.Readable(), "reading"); err != nil {
+		return "", err
+	}
+	line, err := f.reader.ReadString('\n')
+	if err == io.EOF {
+		err = nil
+	}
+	return line, err
+}
+
+// ReadAll returns the rest of the file.
+func (f *File) ReadAll() (string, error) {
+	if err := f.check(f.Readable(), "reading"); err != nil {
+		return "", err
+	}
+	data, err := ioutil.ReadAll(f.reader)
+	return string(data), err
+}
+
+func (f *File) Write(data string) (int, error) {
+	if err := f.check(f.Writable(), "writing"); err != nil {
+		return 0, err
+	}
+	if err := f.discard(); err != nil {
+		return 0, err
+	}
+	return f.file.WriteString(data)
+}
+
+// Seek moves to offset relative to the start, the current
+// position or the end for whence 0, 1 or 2.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if err := f.check(true, ""); err != nil {
+		return 0, err
+	}
+	if whence == io.SeekCurrent {
+		offset -= int64(f.reader.Buffered())
+	}
+	pos, err := f.file.Seek(offset, whence)
+	f.reader.Reset(f.file)
+	return pos, err
+}
+
+func (f *File) Close() error {
+	if f.file == nil {
+		return errClosed
+	}
+	err := f.file.Close()
+	f.file, f.reader = nil, nil
+	return err
+}
+
+// discard drops read ahead data, moving the file back to the
+// position the script is at.
+func (f *File) discard() error {
+	if buffered := f.reader.Buffered(); buffered > 0 {
+		if _, err := f.file.Seek(-int64(buffered), io.SeekCurrent); err != nil {
+			return err
+		}
+		f.reader.Reset(f.file)
+	}
+	return nil
+}
+
+func (f *File) check(allowed bool, operation string) error {
+	if f.file == nil {
+		return errClosed
+	}
+	if !allowed {
+		return fmt.Errorf("file opened with mode %q is not open for %s", f.Mode, operation)
+	}
+	return nil
+}
+
+// OpenFile opens a file checking the mode against the runtime
+// policy, and tracks it so it's closed with the runtime.
+func (r *Runtime) OpenFile(path, mode string) (*File, error) {
+	flag, ok := fileModes[mode]
+	if !ok {
+		return nil, fmt.Errorf("invalid file mode %q, expected r, w, a or rw", mode)
+	}
+	if mode == "r" || mode == "rw" {
+		if err := r.Policy.CheckRead(path); err != nil {
+			return nil, err
+		}
+	}
+	if mode != "r" {
+		if err := r.Policy.CheckWrite(path); err != nil {
+			return nil, err
+		}
+	}
+	file, err := os.OpenFile(path, flag, 0644)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{Path: path, Mode: mode, file: file, reader: bufio.NewReader(file)}
+	r.files = append(r.files, f)
+	return f, nil
+}
+
+func (r *Runtime) untrack(file *File) {
+	for i, f := range r.files {
+		if f == file {
+			r.files = append(r.files[:i], r.files[i+1:]...)
+			return
+		}
+	}
+}
+
+// Close closes every file still open by the runtime, once the
+// program or the session using it is done.
+func (r *Runtime) Close() {
+	for _, f := range r.files {
+		if !f.Closed() {
+			_ = f.Close()
+		}
+	}
+	r.files = nil
+}
@@ -0,0 +1,50 @@
+package object
+
+// Generator runs a function lazily, each call to Next resuming
+// it until it yields a value or returns. Iterating it goes on
+// from where it is, never starting over.
+type Generator struct {
+	Name   string
+	resume func() (Object, bool)
+	done   bool
+}
+
+func NewGenerator(name string, resume func() (Object, bool)) *Generator {
+	return &Generator{Name: name, resume: resume}
+}
+
+func (g *Generator) Type() Type      { return GENERATOR }
+func (g *Generator) Inspect() string { return "generator " + g.Name }
+
+// Next returns the next value yielded, or false once the
+// function returned.
+func (g *Generator) Next() (Object, bool) {
+	if g.done {
+		return nil, false
+	}
+	value, ok := g.resume()
+	if !ok {
+		g.done = true
+	} else if value == nil {
+		value = &Null{}
+	}
+	return value, ok
+}
+
+func (g *Generator) Iterator() Iterator {
+	return &generatorIterator{Generator: g}
+}
+
+type generatorIterator struct {
+	*Generator
+	index int64
+}
+
+func (it *generatorIterator) Next() (Object, Object, bool) {
+	value, ok := it.Generator.Next()
+	if !ok {
+		return nil, nil, false
+	}
+	it.index++
+	return &Integer{Value: it.index - 1}, value, true
+}
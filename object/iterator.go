@@ -0,0 +1,119 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// Iterable objects can be walked by for-in loops, each call
+// to Iterator starting over from the first element.
+type Iterable interface {
+	Object
+	Iterator() Iterator
+}
+
+// Iterator yields the elements of an iterable one at a time,
+// returning false once done. The key is the position of the
+// element, or its key for maps.
+type Iterator interface {
+	Next() (key, value Object, ok bool)
+}
+
+// Range is the lazy sequence of integers from Start up to End,
+// excluded, moving by Step.
+type Range struct {
+	Start int64
+	End   int64
+	Step  int64
+}
+
+func (r *Range) Type() Type { return RANGE }
+func (r *Range) Inspect() string {
+	return fmt.Sprintf("range(%d, %d, %d)", r.Start, r.End, r.Step)
+}
+
+func (r *Range) Iterator() Iterator {
+	return &rangeIterator{Range: r, next: r.Start}
+}
+
+type rangeIterator struct {
+	*Range
+	next  int64
+	index int64
+	done  bool
+}
+
+func (it *rangeIterator) Next() (Object, Object, bool) {
+	if it.done || it.Step > 0 && it.next >= it.End || it.Step < 0 && it.next <= it.End {
+		return nil, nil, false
+	}
+	value := it.next
+	// Stop instead of wrapping around at the int64 bounds
+	if it.Step > 0 && value > math.MaxInt64-it.Step || it.Step < 0 && value < math.MinInt64-it.Step {
+		it.done = true
+	}
+	it.next += it.Step
+	it.index++
+	return &Integer{Value: it.index - 1}, &Integer{Value: value}, true
+}
+
+func (s *String) Iterator() Iterator {
+	return &stringIterator{value: s.Value}
+}
+
+// stringIterator yields the characters of a string, decoded
+// as UTF-8.
+type stringIterator struct {
+	value  string
+	offset int
+	index  int64
+}
+
+func (it *stringIterator) Next() (Object, Object, bool) {
+	if it.offset >= len(it.value) {
+		return nil, nil, false
+	}
+	_, size := utf8.DecodeRuneInString(it.value[it.offset:])
+	char := it.value[it.offset : it.offset+size]
+	it.offset += size
+	it.index++
+	return &Integer{Value: it.index - 1}, &String{Value: char}, true
+}
+
+func (a *Array) Iterator() Iterator {
+	return &arrayIterator{array: a}
+}
+
+type arrayIterator struct {
+	array *Array
+	index int
+}
+
+func (it *arrayIterator) Next() (Object, Object, bool) {
+	if it.index >= len(it.array.Elements) {
+		return nil, nil, false
+	}
+	it.index++
+	return &Integer{Value: int64(it.index - 1)}, it.array.Elements[it.index-1], true
+}
+
+func (m *Map) Iterator() Iterator {
+	return &mapIterator{hash: m}
+}
+
+// mapIterator yields the pairs in insertion order, including
+// the ones added while iterating.
+type mapIterator struct {
+	hash  *Map
+	index int
+}
+
+func (it *mapIterator) Next() (Object, Object, bool) {
+	if it.index >= len(it.hash.order) {
+		return nil, nil, false
+	}
+	pair := it.hash.pairs[it.hash.order[it.index]]
+	it.index++
+	return pair.Key, pair.Value, true
+}
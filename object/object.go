@@ -3,46 +3,252 @@ package object
 import (
 	"fmt"
 	"github.com/Onelio/Eldrlang/parser"
+	"strings"
+)
+
+type Type string
+
+const (
+	ANY       Type = "any"
+	NULL      Type = "null"
+	INTEGER   Type = "integer"
+	BOOLEAN   Type = "boolean"
+	STRING    Type = "string"
+	FUNCTION  Type = "function"
+	BUILTIN   Type = "builtin"
+	ARRAY     Type = "array"
+	MAP       Type = "map"
+	FILE      Type = "file"
+	RANGE     Type = "range"
+	TYPE      Type = "type"
+	STRUCT    Type = "struct"
+	CLASS     Type = "class"
+	INSTANCE  Type = "instance"
+	ENUM      Type = "enum"
+	GENERATOR Type = "generator"
 )
 
 type Object interface {
+	Type() Type
 	Inspect() string
 }
 
 type Null struct{}
 
+func (n *Null) Type() Type      { return NULL }
 func (n *Null) Inspect() string { return "null" }
 
 type Integer struct {
 	Value int64
 }
 
+func (i *Integer) Type() Type      { return INTEGER }
 func (i *Integer) Inspect() string { return fmt.Sprintf("%d", i.Value) }
 
 type Boolean struct {
 	Value bool
 }
 
+func (b *Boolean) Type() Type      { return BOOLEAN }
 func (b *Boolean) Inspect() string { return fmt.Sprintf("%t", b.Value) }
 
 type String struct {
 	Value string
 }
 
+func (s *String) Type() Type      { return STRING }
 func (s *String) Inspect() string { return s.Value }
 
+// Function returns a generator when called if Generator,
+// instead of running its body.
 type Function struct {
+	Name       string
 	Parameters []*parser.Identifier
 	Body       *parser.Block
+	Generator  bool
 }
 
+func (f *Function) Type() Type { return FUNCTION }
 func (f *Function) Inspect() string {
 	return "function"
 }
 
-type Builtin struct {
-	Size int
-	Fun  BuiltFun
+type Array struct {
+	Elements []Object
+}
+
+func (a *Array) Type() Type { return ARRAY }
+func (a *Array) Inspect() string {
+	var elements []string
+	for _, elem := range a.Elements {
+		elements = append(elements, inspect(elem))
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// Map keeps its pairs in insertion order. Only integers,
+// strings and booleans may be used as keys.
+type Map struct {
+	pairs map[interface{}]*mapPair
+	order []interface{}
+}
+
+type mapPair struct {
+	Key   Object
+	Value Object
+}
+
+func NewMap() *Map {
+	return &Map{pairs: make(map[interface{}]*mapPair)}
+}
+
+func (m *Map) Set(key, value Object) bool {
+	hash, ok := hashKey(key)
+	if !ok {
+		return false
+	}
+	if pair, exists := m.pairs[hash]; exists {
+		pair.Value = value
+		return true
+	}
+	m.pairs[hash] = &mapPair{Key: key, Value: value}
+	m.order = append(m.order, hash)
+	return true
+}
+
+func (m *Map) Get(key Object) Object {
+	hash, ok := hashKey(key)
+	if !ok {
+		return nil
+	}
+	if pair, exists := m.pairs[hash]; exists {
+		return pair.Value
+	}
+	return nil
 }
 
-func (b *Builtin) Inspect() string { return "builtin function" }
+func (m *Map) Keys() []Object {
+	keys := make([]Object, 0, len(m.order))
+	for _, hash := range m.order {
+		keys = append(keys, m.pairs[hash].Key)
+	}
+	return keys
+}
+
+func (m *Map) Len() int {
+	return len(m.order)
+}
+
+func (m *Map) Type() Type { return MAP }
+func (m *Map) Inspect() string {
+	var pairs []string
+	for _, hash := range m.order {
+		pair := m.pairs[hash]
+		pairs = append(pairs, inspect(pair.Key)+": "+inspect(pair.Value))
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// Equals compares values structurally, and any other object
+// by identity.
+func Equals(a, b Object) bool {
+	switch x := a.(type) {
+	case nil, *Null:
+		switch b.(type) {
+		case nil, *Null:
+			return true
+		}
+		return false
+	case *Integer, *BigInt:
+		i, _ := ToBig(a)
+		j, ok := ToBig(b)
+		return ok && i.Cmp(j) == 0
+	case *String:
+		y, ok := b.(*String)
+		return ok && x.Value == y.Value
+	case *Boolean:
+		y, ok := b.(*Boolean)
+		return ok && x.Value == y.Value
+	case *Array:
+		y, ok := b.(*Array)
+		if !ok || len(x.Elements) != len(y.Elements) {
+			return false
+		}
+		for i := range x.Elements {
+			if !Equals(x.Elements[i], y.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *Range:
+		y, ok := b.(*Range)
+		return ok && *x == *y
+	case *Map:
+		y, ok := b.(*Map)
+		if !ok || x.Len() != y.Len() {
+			return false
+		}
+		for _, key := range x.Keys() {
+			if !Equals(x.Get(key), y.Get(key)) {
+				return false
+			}
+		}
+		return true
+	case *EnumValue:
+		y, ok := b.(*EnumValue)
+		if !ok || x.Variant != y.Variant || len(x.Values) != len(y.Values) {
+			return false
+		}
+		for i := range x.Values {
+			if !Equals(x.Values[i], y.Values[i]) {
+				return false
+			}
+		}
+		return true
+	case *Struct:
+		y, ok := b.(*Struct)
+		if !ok || x.Name() != y.Name() || len(x.Values) != len(y.Values) {
+			return false
+		}
+		for i := range x.Values {
+			if !Equals(x.Values[i], y.Values[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// bigKey keeps the keys of big integers apart from strings.
+type bigKey string
+
+func hashKey(key Object) (interface{}, bool) {
+	switch k := key.(type) {
+	case *Integer:
+		return k.Value, true
+	case *BigInt:
+		return bigKey(k.Value.String()), true
+	case *String:
+		return k.Value, true
+	case *Boolean:
+		return k.Value, true
+	}
+	return nil, false
+}
+
+// Inspect returns the representation of an object within
+// others, quoting strings.
+func Inspect(obj Object) string {
+	return inspect(obj)
+}
+
+func inspect(obj Object) string {
+	if obj == nil {
+		return "null"
+	}
+	if str, ok := obj.(*String); ok {
+		return fmt.Sprintf("%q", str.Value)
+	}
+	return obj.Inspect()
+}
@@ -0,0 +1,120 @@
+package object
+
+import (
+	"fmt"
+	"github.com/Onelio/Eldrlang/util"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Policy lists the capabilities builtins are granted when running
+// sandboxed. Its zero value denies everything, while a nil policy
+// grants everything.
+type Policy struct {
+	Read   []string
+	Write  []string
+	Stdin  bool
+	Stdout bool
+	Stderr bool
+	Exec   bool
+	Net    bool
+}
+
+type PermissionError struct {
+	Access string
+	Target string
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf(util.AccessDenied, e.Access, e.Target)
+}
+
+func (p *Policy) CheckRead(path string) error {
+	if p == nil || allowedPath(p.Read, path) {
+		return nil
+	}
+	return &PermissionError{Access: "read", Target: path}
+}
+
+func (p *Policy) CheckWrite(path string) error {
+	if p == nil || allowedPath(p.Write, path) {
+		return nil
+	}
+	return &PermissionError{Access: "write", Target: path}
+}
+
+func (p *Policy) CheckStdin() error {
+	if p == nil || p.Stdin {
+		return nil
+	}
+	return &PermissionError{Access: "read", Target: "stdin"}
+}
+
+func (p *Policy) CheckStdout() error {
+	if p == nil || p.Stdout {
+		return nil
+	}
+	return &PermissionError{Access: "write", Target: "stdout"}
+}
+
+func (p *Policy) CheckStderr() error {
+	if p == nil || p.Stderr {
+		return nil
+	}
+	return &PermissionError{Access: "write", Target: "stderr"}
+}
+
+// CheckExec and CheckNet are meant for host registered builtins,
+// as the default ones can neither run processes nor reach the
+// network.
+func (p *Policy) CheckExec(command string) error {
+	if p == nil || p.Exec {
+		return nil
+	}
+	return &PermissionError{Access: "exec", Target: command}
+}
+
+func (p *Policy) CheckNet(address string) error {
+	if p == nil || p.Net {
+		return nil
+	}
+	return &PermissionError{Access: "net", Target: address}
+}
+
+func allowedPath(prefixes []string, path string) bool {
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return false
+	}
+	for _, prefix := range prefixes {
+		allowed, err := resolvePath(prefix)
+		if err != nil {
+			continue
+		}
+		if resolved == allowed || strings.HasPrefix(resolved, allowed+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath returns the absolute path with symbolic links
+// resolved, so links can't be used to escape a prefix. Files
+// that don't exist yet are resolved through their directory.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved, nil
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	dir, err := resolvePath(filepath.Dir(abs))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.Base(abs)), nil
+}
@@ -0,0 +1,187 @@
+package object
+
+import (
+	"fmt"
+	"github.com/Onelio/Eldrlang/util"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type BuiltFun func(rt *Runtime, args ...Object) (Object, error)
+
+// Param describes a builtin parameter. A parameter without
+// types accepts any object.
+type Param struct {
+	Name     string
+	Types    []Type
+	Optional bool
+	Variadic bool
+}
+
+func (p Param) accepts(arg Object) bool {
+	if len(p.Types) == 0 {
+		return true
+	}
+	for _, t := range p.Types {
+		if t == ANY || arg != nil && arg.Type() == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Param) String() string {
+	var out strings.Builder
+	out.WriteString(p.Name)
+	if p.Variadic {
+		out.WriteString("...")
+	}
+	if p.Optional {
+		out.WriteString("?")
+	}
+	if len(p.Types) > 0 {
+		out.WriteString(": " + typeList(p.Types, "|"))
+	}
+	return out.String()
+}
+
+type Builtin struct {
+	Name   string
+	Params []Param
+	Doc    string
+	Fun    BuiltFun
+}
+
+func (b *Builtin) Type() Type      { return BUILTIN }
+func (b *Builtin) Inspect() string { return "builtin function" }
+
+func (b *Builtin) Signature() string {
+	var params []string
+	for _, p := range b.Params {
+		params = append(params, p.String())
+	}
+	return b.Name + "(" + strings.Join(params, ", ") + ")"
+}
+
+// Arity returns the minimum and maximum amount of arguments,
+// the maximum being -1 for variadic builtins.
+func (b *Builtin) Arity() (int, int) {
+	min, max := 0, len(b.Params)
+	for _, p := range b.Params {
+		if p.Variadic {
+			max = -1
+		}
+		if !p.Optional && !p.Variadic {
+			min++
+		}
+	}
+	return min, max
+}
+
+// Check validates the amount and types of the arguments.
+func (b *Builtin) Check(args []Object) error {
+	min, max := b.Arity()
+	if len(args) < min || max >= 0 && len(args) > max {
+		expected := fmt.Sprintf("%d", min)
+		switch {
+		case max < 0:
+			expected = fmt.Sprintf("at least %d", min)
+		case max != min:
+			expected = fmt.Sprintf("%d to %d", min, max)
+		}
+		return fmt.Errorf(util.InvalidArgNum, b.Name, expected, len(args))
+	}
+	for i, arg := range args {
+		param := b.Params[len(b.Params)-1]
+		if i < len(b.Params) {
+			param = b.Params[i]
+		}
+		if !param.accepts(arg) {
+			got := NULL
+			if arg != nil {
+				got = arg.Type()
+			}
+			return fmt.Errorf(util.InvalidArgTyp, i+1, b.Name, oneOf(param.Types), got)
+		}
+	}
+	return nil
+}
+
+// Builtins is a table of builtin functions owned by a runtime,
+// so every interpreter can have its own set.
+type Builtins struct {
+	mu    sync.RWMutex
+	table map[string]*Builtin
+}
+
+// NewBuiltins returns a table with the default builtins.
+func NewBuiltins() *Builtins {
+	b := &Builtins{table: make(map[string]*Builtin)}
+	for _, builtin := range defaultBuiltins() {
+		_ = b.Register(builtin)
+	}
+	return b
+}
+
+func (b *Builtins) Register(builtin *Builtin) error {
+	if builtin.Name == "" || builtin.Fun == nil {
+		return fmt.Errorf("builtin requires a name and a function")
+	}
+	optional := false
+	for i, p := range builtin.Params {
+		if p.Variadic && i != len(builtin.Params)-1 {
+			return fmt.Errorf("builtin %q: only the last parameter can be variadic", builtin.Name)
+		}
+		if optional && !p.Optional && !p.Variadic {
+			return fmt.Errorf("builtin %q: required parameter %q after an optional one", builtin.Name, p.Name)
+		}
+		optional = optional || p.Optional
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.table[builtin.Name] = builtin
+	return nil
+}
+
+func (b *Builtins) Remove(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.table, name)
+}
+
+func (b *Builtins) Get(name string) *Builtin {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.table[name]
+}
+
+func (b *Builtins) Names() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var names []string
+	for name := range b.table {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func typeList(types []Type, sep string) string {
+	if len(types) == 0 {
+		return string(ANY)
+	}
+	var names []string
+	for _, t := range types {
+		names = append(names, string(t))
+	}
+	return strings.Join(names, sep)
+}
+
+func oneOf(types []Type) string {
+	list := typeList(types, ", ")
+	if i := strings.LastIndex(list, ", "); i >= 0 {
+		list = list[:i] + " or " + list[i+2:]
+	}
+	return list
+}
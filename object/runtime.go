@@ -1,11 +1,39 @@
 package object
 
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// Runtime holds the state shared by builtins. The streams
+// default to the ones of the process and may be replaced
+// before evaluating.
 type Runtime struct {
-	context *Context
+	Builtins *Builtins
+	Policy   *Policy
+	Stdin    io.Reader
+	Stdout   io.Writer
+	Stderr   io.Writer
+	context  *Context
+	files    []*File
+	input    *bufio.Reader
+	source   io.Reader
 }
 
 func NewRuntime() *Runtime {
-	return &Runtime{context: NewContext()}
+	return &Runtime{
+		Builtins: NewBuiltins(),
+		Stdin:    os.Stdin,
+		Stdout:   os.Stdout,
+		Stderr:   os.Stderr,
+		context:  NewContext(),
+	}
+}
+
+func (r *Runtime) Context() *Context {
+	return r.context
 }
 
 func (r *Runtime) PushChild() {
@@ -35,3 +63,76 @@ func (r *Runtime) GetValue(name string) Object {
 func (r *Runtime) SetValue(name string, val Object) {
 	r.context.Set(name, val)
 }
+
+func (r *Runtime) SetConstant(name string, val Object) {
+	r.context.SetConstant(name, val)
+}
+
+// IsConstant tells whether the closest context defining name
+// binds it as a constant.
+func (r *Runtime) IsConstant(name string) bool {
+	for context := r.context; context != nil; context = context.parent {
+		if _, ok := context.store[name]; ok {
+			return context.IsConstant(name)
+		}
+	}
+	return false
+}
+
+// Assign updates the closest context defining name, returning
+// false when none does.
+func (r *Runtime) Assign(name string, val Object) bool {
+	for context := r.context; context != nil; context = context.parent {
+		if _, ok := context.store[name]; ok {
+			context.Set(name, val)
+			return true
+		}
+	}
+	return false
+}
+
+// Switch makes ctx the current context, returning the one it
+// replaces, for code resumed in contexts of its own.
+func (r *Runtime) Switch(ctx *Context) *Context {
+	previous := r.context
+	r.context = ctx
+	return previous
+}
+
+// Flatten replaces the contexts from the current one up to
+// base, excluded, with a single one binding the names visible
+// through them, so code run in place of theirs still sees them
+// without the contexts piling up.
+func (r *Runtime) Flatten(base *Context) {
+	flat := NewContext()
+	for context := r.context; context != base && context != nil; context = context.parent {
+		for name, val := range context.store {
+			if _, ok := flat.store[name]; !ok {
+				flat.store[name] = val
+				flat.consts[name] = context.consts[name]
+			}
+		}
+	}
+	flat.parent, r.context = base, flat
+}
+
+// Unwind pops contexts until ctx, or the global one, is the
+// current context again.
+func (r *Runtime) Unwind(ctx *Context) {
+	for r.context != ctx && r.context.parent != nil {
+		r.context = r.context.parent
+	}
+}
+
+// ReadLine reads a line from Stdin without its line break,
+// buffering it for as long as the stream is not replaced.
+func (r *Runtime) ReadLine() (string, error) {
+	if r.input == nil || r.source != r.Stdin {
+		r.input, r.source = bufio.NewReader(r.Stdin), r.Stdin
+	}
+	line, err := r.input.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
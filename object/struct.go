@@ -0,0 +1,79 @@
+package object
+
+import (
+	"strings"
+)
+
+// StructType is the definition of a struct, its fields in the
+// order they were declared.
+type StructType struct {
+	Name   string
+	Fields []string
+}
+
+func (s *StructType) Type() Type      { return TYPE }
+func (s *StructType) Inspect() string { return "struct " + s.Name }
+
+func (s *StructType) index(field string) int {
+	for i, name := range s.Fields {
+		if name == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// New returns a struct of this type with every field null.
+func (s *StructType) New() *Struct {
+	values := make([]Object, len(s.Fields))
+	for i := range values {
+		values[i] = &Null{}
+	}
+	return &Struct{Def: s, Values: values}
+}
+
+// Record is implemented by the objects whose fields are read
+// and written with a dot, named after their type.
+type Record interface {
+	Object
+	Name() string
+	Get(field string) Object
+	Set(field string, value Object) bool
+}
+
+type Struct struct {
+	Def    *StructType
+	Values []Object
+}
+
+func (s *Struct) Type() Type { return STRUCT }
+func (s *Struct) Inspect() string {
+	var fields []string
+	for i, name := range s.Def.Fields {
+		fields = append(fields, name+": "+inspect(s.Values[i]))
+	}
+	return s.Def.Name + "{" + strings.Join(fields, ", ") + "}"
+}
+
+// Name returns the name of the type of the struct.
+func (s *Struct) Name() string {
+	return s.Def.Name
+}
+
+func (s *Struct) Get(field string) Object {
+	if i := s.Def.index(field); i >= 0 {
+		return s.Values[i]
+	}
+	return nil
+}
+
+func (s *Struct) Set(field string, value Object) bool {
+	if value == nil {
+		value = &Null{}
+	}
+	if i := s.Def.index(field); i >= 0 {
+		s.Values[i] = value
+		return true
+	}
+	return false
+}
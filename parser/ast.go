@@ -3,6 +3,7 @@ package parser
 import (
 	"bytes"
 	"fmt"
+	"github.com/Onelio/Eldrlang/lexer"
 	"github.com/Onelio/Eldrlang/util"
 	"strings"
 )
@@ -25,6 +26,7 @@ type Package struct {
 	Namespace string
 	Nodes     []Node
 	Errors    util.Errors
+	Warnings  util.Errors
 }
 
 func (p *Package) String() string {
@@ -32,7 +34,7 @@ func (p *Package) String() string {
 	var i int
 	for _, line := range p.Nodes {
 		switch line.(type) {
-		case *Block, *Conditional, *Function, *Loop:
+		case *Block, *Conditional, *Function, *Loop, *While, *For, *ForIn, *Labeled, *Match:
 			lines := strings.Split(line.String(), "\n")
 			for _, sub := range lines {
 				_, _ = fmt.Fprintf(&out, "%d\t%s\n", i, sub)
@@ -45,3 +47,79 @@ func (p *Package) String() string {
 	}
 	return out.String()
 }
+
+// TokenOf returns the token a node was created from, which
+// locates it in the source.
+func TokenOf(node Node) lexer.Token {
+	switch n := node.(type) {
+	case *Identifier:
+		return n.Token
+	case *Boolean:
+		return n.Token
+	case *Integer:
+		return n.Token
+	case *String:
+		return n.Token
+	case *Variable:
+		return n.Token
+	case *Constant:
+		return n.Token
+	case *Assign:
+		return n.Token
+	case *Update:
+		return n.Token
+	case *Prefix:
+		return n.Token
+	case *Infix:
+		return n.Token
+	case *FuncCall:
+		return n.Token
+	case *Return:
+		return n.Token
+	case *Break:
+		return n.Token
+	case *Block:
+		return n.Token
+	case *Conditional:
+		return n.Token
+	case *Loop:
+		return n.Token
+	case *While:
+		return n.Token
+	case *For:
+		return n.Token
+	case *ForIn:
+		return n.Token
+	case *Labeled:
+		return n.Token
+	case *Match:
+		return n.Token
+	case *MatchArm:
+		return n.Token
+	case *ArrayPattern:
+		return n.Token
+	case *MapPattern:
+		return n.Token
+	case *Struct:
+		return n.Token
+	case *StructLiteral:
+		return n.Token
+	case *Field:
+		return n.Token
+	case *Class:
+		return n.Token
+	case *Self:
+		return n.Token
+	case *Super:
+		return n.Token
+	case *Enum:
+		return n.Token
+	case *VariantPattern:
+		return n.Token
+	case *Yield:
+		return n.Token
+	case *Function:
+		return n.Token
+	}
+	return lexer.Token{}
+}
@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"bytes"
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/util"
+)
+
+// Class declares the methods of its instances, the ones of
+// Parent being inherited unless declared again.
+type Class struct {
+	Token   lexer.Token
+	End     lexer.Token
+	Name    *Identifier
+	Parent  *Identifier
+	Methods []*Function
+}
+
+func (c *Class) Literal() string { return c.Token.Literal }
+func (c *Class) String() string {
+	var out bytes.Buffer
+	out.WriteString("class ")
+	out.WriteString(c.Name.String())
+	if c.Parent != nil {
+		out.WriteString(" : " + c.Parent.String())
+	}
+	out.WriteString(" {\n")
+	for _, method := range c.Methods {
+		out.WriteString("\t" + method.String() + "\n")
+	}
+	out.WriteString("}")
+	return out.String()
+}
+
+func (p *Parser) newClass() Statement {
+	class := &Class{Token: p.token}
+
+	if p.nextToken() != lexer.IDENT {
+		err := util.NewError(p.token, util.ExpectedIdent, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	class.Name = p.newIdentifier().(*Identifier)
+	p.declare(class.Name, lexer.CLASS)
+
+	if p.isPeekToken(lexer.COLON) {
+		p.nextToken() // Skip class name
+		if p.nextToken() != lexer.IDENT {
+			err := util.NewError(p.token, util.ExpectedIdent, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+		class.Parent = p.newIdentifier().(*Identifier)
+	}
+	if p.nextToken() != lexer.LBRACE {
+		err := util.NewError(p.token, util.ExpectedBrace, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	// Methods are only reachable through the instances
+	p.pushScope()
+	defer p.popScope()
+	outer := p.class
+	p.class = class
+	defer func() { p.class = outer }()
+
+	for p.nextToken() != lexer.RBRACE {
+		if !p.isToken(lexer.FUNCTION) {
+			if p.isToken(lexer.EOF) {
+				err := util.NewError(p.token, util.UnexpectedEOF, "}")
+				p.errors.Add(err)
+			} else {
+				err := util.NewError(p.token, util.ExpectedMethd, p.token.Literal)
+				p.errors.Add(err)
+			}
+			return nil
+		}
+		method, ok := p.newFunction().(*Function)
+		if !ok {
+			return nil
+		}
+		class.Methods = append(class.Methods, method)
+	}
+	class.End = p.token
+	return class
+}
+
+// Self is the instance the running method was called on.
+type Self struct {
+	Token lexer.Token
+}
+
+func (s *Self) Literal() string { return s.Token.Literal }
+func (s *Self) String() string  { return s.Token.Literal }
+
+// Super looks up the methods of the parent of the class the
+// running method belongs to, called on the same instance.
+type Super struct {
+	Token lexer.Token
+}
+
+func (s *Super) Literal() string { return s.Token.Literal }
+func (s *Super) String() string  { return s.Token.Literal }
+
+func (p *Parser) newSelf() Expression {
+	if p.class == nil {
+		err := util.NewError(p.token, util.OutsideOfClas, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	return &Self{Token: p.token}
+}
+
+func (p *Parser) newSuper() Expression {
+	switch {
+	case p.class == nil:
+		err := util.NewError(p.token, util.OutsideOfClas, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	case p.class.Parent == nil:
+		err := util.NewError(p.token, util.NoParentClass, p.class.Name.Value)
+		p.errors.Add(err)
+		return nil
+	case !p.isPeekToken(lexer.DOT):
+		p.nextToken() // Skip super token
+		err := util.NewError(p.token, util.ExpectedDotOp, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	return &Super{Token: p.token}
+}
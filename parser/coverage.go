@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/util"
+	"strings"
+)
+
+// coverage is a match, or a chain of ifs comparing the same
+// value, that may be over the variants of an enum. It is
+// checked once the package is parsed, as function bodies may
+// refer to enums declared after them.
+type coverage struct {
+	token    lexer.Token
+	kind     string
+	scopes   []map[string]lexer.Type
+	patterns []*VariantPattern
+	covered  []bool
+	conds    []*Infix
+}
+
+// matchCoverage returns the coverage of a match with only
+// variant patterns, unless an arm matches anything.
+func (p *Parser) matchCoverage(m *Match) *coverage {
+	c := &coverage{token: m.Token, kind: "match", scopes: p.snapshot()}
+	for _, arm := range m.Arms {
+		for _, pattern := range arm.Patterns {
+			switch pat := pattern.(type) {
+			case *Identifier:
+				if arm.Guard == nil {
+					return nil
+				}
+			case *VariantPattern:
+				c.patterns = append(c.patterns, pat)
+				c.covered = append(c.covered, arm.Guard == nil && irrefutable(pat.Params))
+			default:
+				return nil
+			}
+		}
+	}
+	if len(c.patterns) == 0 {
+		return nil
+	}
+	return c
+}
+
+func irrefutable(patterns []Node) bool {
+	for _, pattern := range patterns {
+		if _, ok := pattern.(*Identifier); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ifCoverage returns the coverage of a chain of at least two
+// ifs testing equalities, without a last else.
+func (p *Parser) ifCoverage(cond *Conditional) *coverage {
+	c := &coverage{token: cond.Token, kind: "if", scopes: p.snapshot()}
+	for {
+		inf, ok := cond.Require.(*Infix)
+		if !ok || inf.Operator != "==" {
+			return nil
+		}
+		c.conds = append(c.conds, inf)
+		next, ok := cond.Else.(*Conditional)
+		if !ok {
+			if cond.Else != nil || len(c.conds) < 2 {
+				return nil
+			}
+			return c
+		}
+		cond = next
+	}
+}
+
+// snapshot keeps the scopes a coverage is in, which still get
+// the declarations following it.
+func (p *Parser) snapshot() []map[string]lexer.Type {
+	return append([]map[string]lexer.Type(nil), p.scopes...)
+}
+
+// check warns of the variants missed by a coverage over an
+// enum, if it is over one.
+func (c *coverage) check(enums map[string]*Enum, warnings *util.Errors) {
+	var (
+		enum    *Enum
+		subject string
+		covered = make(map[string]bool)
+	)
+	// Every variant must be of the same enum
+	add := func(exp Expression, name string, covers bool) bool {
+		def := c.enumOf(exp, enums)
+		if def == nil || enum != nil && def != enum {
+			return false
+		}
+		enum = def
+		covered[name] = covered[name] || covers
+		return true
+	}
+	for i, pattern := range c.patterns {
+		if pattern.Enum == nil || !add(pattern.Enum, pattern.Name.Value, c.covered[i]) {
+			return
+		}
+	}
+	for i, inf := range c.conds {
+		variant, other := inf.Right, inf.Left
+		if field, ok := variant.(*Field); !ok || c.enumOf(field.Object, enums) == nil {
+			variant, other = inf.Left, inf.Right
+		}
+		field, ok := variant.(*Field)
+		if !ok || other == nil || i > 0 && other.String() != subject {
+			return
+		}
+		subject = other.String()
+		if !add(field.Object, field.Name.Value, true) {
+			return
+		}
+	}
+	var missing []string
+	for _, variant := range enum.Variants {
+		if !covered[variant.Name.Value] {
+			missing = append(missing, enum.Name.Value+"."+variant.Name.Value)
+		}
+	}
+	if len(missing) > 0 {
+		warning := util.NewWarning(c.token, util.NotExhaustive, c.kind, enum.Name.Value, strings.Join(missing, ", "))
+		warnings.Add(warning)
+	}
+}
+
+// enumOf returns the enum an expression names, if any, looking
+// past the functions the coverage is in.
+func (c *coverage) enumOf(exp Expression, enums map[string]*Enum) *Enum {
+	ident, ok := exp.(*Identifier)
+	if !ok {
+		return nil
+	}
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if kind, ok := c.scopes[i][ident.Value]; ok {
+			if kind != lexer.ENUM {
+				return nil
+			}
+			return enums[ident.Value]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"bytes"
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/util"
+	"strings"
+)
+
+type Enum struct {
+	Token    lexer.Token
+	Name     *Identifier
+	Variants []*Variant
+}
+
+func (e *Enum) Literal() string { return e.Token.Literal }
+func (e *Enum) String() string {
+	var out bytes.Buffer
+	var variants []string
+	for _, variant := range e.Variants {
+		variants = append(variants, variant.String())
+	}
+	out.WriteString("enum ")
+	out.WriteString(e.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(variants, ", "))
+	out.WriteString(" }")
+	return out.String()
+}
+
+// Variant is a value of an enum, or when Payload a constructor
+// of values carrying one for each of its Params.
+type Variant struct {
+	Name    *Identifier
+	Params  []*Identifier
+	Payload bool
+}
+
+func (v *Variant) Literal() string { return v.Name.Literal() }
+func (v *Variant) String() string {
+	if !v.Payload {
+		return v.Name.String()
+	}
+	var params []string
+	for _, param := range v.Params {
+		params = append(params, param.String())
+	}
+	return v.Name.String() + "(" + strings.Join(params, ", ") + ")"
+}
+
+func (p *Parser) newEnum() Statement {
+	enum := &Enum{Token: p.token}
+
+	if p.nextToken() != lexer.IDENT {
+		err := util.NewError(p.token, util.ExpectedIdent, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	enum.Name = p.newIdentifier().(*Identifier)
+	p.declare(enum.Name, lexer.ENUM)
+	p.enums[enum.Name.Value] = enum
+
+	if p.nextToken() != lexer.LBRACE {
+		err := util.NewError(p.token, util.ExpectedBrace, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	seen := make(map[string]bool)
+	for p.nextToken() != lexer.RBRACE {
+		if !p.isToken(lexer.IDENT) {
+			err := util.NewError(p.token, util.ExpectedIdent, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+		variant := &Variant{Name: p.newIdentifier().(*Identifier)}
+		if seen[variant.Name.Value] {
+			err := util.NewError(variant.Name.Token, util.DuplicateDecl, variant.Name.Value)
+			p.errors.Add(err)
+		}
+		seen[variant.Name.Value] = true
+		if p.isPeekToken(lexer.LPAREN) {
+			p.nextToken() // Skip variant name
+			variant.Payload = true
+			for _, param := range p.newParameters() {
+				ident, valid := param.(*Identifier)
+				if !valid {
+					err := util.NewError(p.token, util.ExpectedIdent, param.String())
+					p.errors.Add(err)
+					return nil
+				}
+				variant.Params = append(variant.Params, ident)
+			}
+		}
+		enum.Variants = append(enum.Variants, variant)
+		if p.isPeekToken(lexer.COMMA) {
+			p.nextToken() // Skip comma token
+		}
+	}
+	return enum
+}
+
+// VariantPattern matches the values of a variant, destructuring
+// their payload.
+type VariantPattern struct {
+	Token   lexer.Token
+	Enum    *Identifier
+	Name    *Identifier
+	Params  []Node
+	Payload bool
+}
+
+func (v *VariantPattern) Literal() string { return v.Token.Literal }
+func (v *VariantPattern) String() string {
+	name := v.Enum.String() + "." + v.Name.String()
+	if !v.Payload {
+		return name
+	}
+	var params []string
+	for _, param := range v.Params {
+		params = append(params, param.String())
+	}
+	return name + "(" + strings.Join(params, ", ") + ")"
+}
+
+func (p *Parser) newVariantPattern() Node {
+	pattern := &VariantPattern{Token: p.token}
+	pattern.Enum = p.newIdentifier().(*Identifier)
+	p.nextToken() // Skip enum name
+	if p.nextToken() != lexer.IDENT {
+		err := util.NewError(p.token, util.ExpectedIdent, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	pattern.Name = p.newIdentifier().(*Identifier)
+	if !p.isPeekToken(lexer.LPAREN) {
+		return pattern
+	}
+	p.nextToken() // Skip variant name
+	p.nextToken() // Skip ( opening
+	pattern.Payload = true
+	for !p.isTokenOrEOF(lexer.RPAREN) {
+		param := p.newPattern()
+		if param == nil {
+			return nil
+		}
+		pattern.Params = append(pattern.Params, param)
+		if p.nextToken() == lexer.COMMA {
+			p.nextToken()
+		}
+	}
+	if p.isToken(lexer.EOF) {
+		err := util.NewError(p.token, util.UnexpectedEOF, ")")
+		p.errors.Add(err)
+		return nil
+	}
+	return pattern
+}
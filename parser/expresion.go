@@ -27,13 +27,84 @@ func (p *Parser) newAssign(left Expression) Expression {
 		Token: p.token,
 		Left:  left,
 	}
+	p.checkConstant(left)
+	expression.Right = p.checkOperand(p.parseOperand())
+	return expression
+}
+
+// parseOperand parses the right side of an assignment, which
+// ends before a semicolon or the delimiter closing a group, a
+// block or a match arm.
+func (p *Parser) parseOperand() Expression {
 	var exp Expression
-	for !p.isPeekToken(lexer.SEMICOLON) {
-		p.nextToken() // Go next first to skip = opcode
+	for !p.isPeekToken(lexer.EOF) {
+		switch p.peekToken() {
+		case lexer.SEMICOLON, lexer.RPAREN, lexer.RBRACE, lexer.COMMA:
+			return exp
+		}
+		p.nextToken() // Go next first to skip the opcode
 		exp = p.parseToken(exp)
 	}
-	expression.Right = exp
-	return expression
+	return exp
+}
+
+// Update applies Operator to Target and Value, or to Target
+// and 1 with ++ and --, and assigns the result to Target.
+type Update struct {
+	Token    lexer.Token
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
+func (u *Update) Literal() string { return u.Token.Literal }
+func (u *Update) String() string {
+	var out bytes.Buffer
+	out.WriteString(u.Target.String())
+	if u.Value == nil {
+		out.WriteString(u.Token.Literal)
+		return out.String()
+	}
+	out.WriteString(" " + u.Token.Literal + " ")
+	out.WriteString(u.Value.String())
+	return out.String()
+}
+
+func (p *Parser) newUpdate(target Expression) Expression {
+	update := &Update{
+		Token:    p.token,
+		Target:   target,
+		Operator: p.token.Literal[:1],
+	}
+	p.checkConstant(target)
+	if p.isToken(lexer.INCREMENT) || p.isToken(lexer.DECREMENT) {
+		return update
+	}
+	update.Value = p.checkOperand(p.parseOperand())
+	return update
+}
+
+// checkOperand reports assignments and updates used as values,
+// as they have none.
+func (p *Parser) checkOperand(exp Expression) Expression {
+	switch exp.(type) {
+	case *Assign, *Update:
+		err := util.NewError(TokenOf(exp), util.IllegalOpeAtt)
+		p.errors.Add(err)
+	}
+	return exp
+}
+
+// checkConstant reports assignments to names known to be
+// constants or types at this point.
+func (p *Parser) checkConstant(target Expression) {
+	if ident, ok := target.(*Identifier); ok {
+		switch p.declaredAs(ident.Value) {
+		case lexer.CONSTANT, lexer.FUNCTION, lexer.STRUCT, lexer.CLASS, lexer.ENUM:
+			err := util.NewError(ident.Token, util.AssignToConst, ident.Value)
+			p.errors.Add(err)
+		}
+	}
 }
 
 type Prefix struct {
@@ -114,6 +185,9 @@ func (fc *FuncCall) String() string {
 func (p *Parser) newFuncCall(function Expression) Expression {
 	exp := &FuncCall{Token: p.token, Function: function}
 	exp.Arguments = p.newParameters()
+	for _, argument := range exp.Arguments {
+		p.checkOperand(argument)
+	}
 	return exp
 }
 
@@ -135,25 +209,79 @@ func (p *Parser) newReturn() Expression {
 		Token: p.token,
 	}
 	p.nextToken()
-	expression.Exp = p.parseExpression()
+	expression.Exp = p.checkOperand(p.parseExpression())
+	return expression
+}
+
+// Yield suspends the generator running it, handing Value to
+// the code resuming it.
+type Yield struct {
+	Token lexer.Token
+	Value Expression
+}
+
+func (y *Yield) Literal() string { return y.Token.Literal }
+func (y *Yield) String() string {
+	if y.Value == nil {
+		return "yield"
+	}
+	return "yield " + y.Value.String()
+}
+
+// newYield makes the function it is in a generator.
+func (p *Parser) newYield() Expression {
+	if p.function == nil {
+		err := util.NewError(p.token, util.OutsideOfFunc, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	p.function.Generator = true
+	expression := &Yield{Token: p.token}
+	p.nextToken()
+	expression.Value = p.checkOperand(p.parseExpression())
 	return expression
 }
 
+// Break is also used for continue statements, telling them
+// apart by the token. Without a label it targets the closest
+// loop.
 type Break struct {
 	Token lexer.Token
+	Label *Identifier
 }
 
 func (b *Break) Literal() string { return b.Token.Literal }
 func (b *Break) String() string {
 	var out bytes.Buffer
-	out.WriteString("break")
+	out.WriteString(b.Token.Literal)
+	if b.Label != nil {
+		out.WriteString(" " + b.Label.String())
+	}
 	return out.String()
 }
 
+func (b *Break) Continue() bool {
+	return b.Token.Type == lexer.CONTINUE
+}
+
 func (p *Parser) newBreak() Expression {
 	b := &Break{
 		Token: p.token,
 	}
+	label := ""
+	if p.isPeekToken(lexer.IDENT) {
+		p.nextToken()
+		b.Label = p.newIdentifier().(*Identifier)
+		label = b.Label.Value
+	}
+	switch {
+	case !p.hasLoop(""):
+		err := util.NewError(b.Token, util.OutsideOfLoop, b.Token.Literal)
+		p.errors.Add(err)
+	case !p.hasLoop(label):
+		err := util.NewError(b.Label.Token, util.LabelNotFound, label)
+		p.errors.Add(err)
+	}
 	if !p.isPeekToken(lexer.SEMICOLON) {
 		err := util.NewError(p.token, util.IllegalExprBr)
 		p.errors.Add(err)
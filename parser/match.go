@@ -0,0 +1,267 @@
+package parser
+
+import (
+	"bytes"
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/util"
+	"strings"
+)
+
+// Match evaluates the body of the first arm with a pattern
+// matching Value, valued as it.
+type Match struct {
+	Token lexer.Token
+	End   lexer.Token
+	Value Expression
+	Arms  []*MatchArm
+}
+
+func (m *Match) Literal() string { return m.Token.Literal }
+func (m *Match) String() string {
+	var out bytes.Buffer
+	out.WriteString("match (")
+	out.WriteString(m.Value.String())
+	out.WriteString(") {\n")
+	for _, arm := range m.Arms {
+		out.WriteString("\t" + arm.String() + ",\n")
+	}
+	out.WriteString("}")
+	return out.String()
+}
+
+// MatchArm holds the alternative patterns of an arm. Patterns
+// are literals, identifiers binding the value, _ matching
+// anything and array, map or enum variant patterns
+// destructuring them.
+type MatchArm struct {
+	Token    lexer.Token
+	Patterns []Node
+	Guard    Expression
+	Body     Node
+}
+
+func (a *MatchArm) Literal() string { return a.Token.Literal }
+func (a *MatchArm) String() string {
+	var out bytes.Buffer
+	var patterns []string
+	for _, pattern := range a.Patterns {
+		patterns = append(patterns, pattern.String())
+	}
+	out.WriteString(strings.Join(patterns, " | "))
+	if a.Guard != nil {
+		out.WriteString(" if " + a.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(a.Body.String())
+	return out.String()
+}
+
+type ArrayPattern struct {
+	Token    lexer.Token
+	Elements []Node
+}
+
+func (a *ArrayPattern) Literal() string { return a.Token.Literal }
+func (a *ArrayPattern) String() string {
+	var elements []string
+	for _, elem := range a.Elements {
+		elements = append(elements, elem.String())
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// MapPattern matches maps holding every key, whatever other
+// keys they have.
+type MapPattern struct {
+	Token  lexer.Token
+	Keys   []Expression
+	Values []Node
+}
+
+func (m *MapPattern) Literal() string { return m.Token.Literal }
+func (m *MapPattern) String() string {
+	var pairs []string
+	for i, key := range m.Keys {
+		pairs = append(pairs, key.String()+": "+m.Values[i].String())
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+func (p *Parser) newMatch() Expression {
+	match := &Match{Token: p.token}
+	// Struct literals are unambiguous within its own braces
+	noStruct := p.noStruct
+	p.noStruct = false
+	defer func() { p.noStruct = noStruct }()
+
+	if p.nextToken() != lexer.LPAREN {
+		err := util.NewError(p.token, util.ExpectedParen, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	match.Value = p.parseGroupExpression()
+
+	if p.nextToken() != lexer.LBRACE {
+		err := util.NewError(p.token, util.ExpectedBrace, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	p.nextToken() // Skip { opening
+	for !p.isTokenOrEOF(lexer.RBRACE) {
+		arm := p.newMatchArm()
+		if arm == nil {
+			return nil
+		}
+		match.Arms = append(match.Arms, arm)
+		p.nextToken()
+	}
+	if p.isToken(lexer.EOF) {
+		err := util.NewError(p.token, util.UnexpectedEOF, "}")
+		p.errors.Add(err)
+		return nil
+	}
+	match.End = p.token
+	if c := p.matchCoverage(match); c != nil {
+		p.coverages = append(p.coverages, c)
+	}
+	return match
+}
+
+func (p *Parser) newMatchArm() *MatchArm {
+	arm := &MatchArm{Token: p.token}
+	// Names bound by the patterns belong to the arm
+	p.pushScope()
+	defer p.popScope()
+
+	for {
+		pattern := p.newPattern()
+		if pattern == nil {
+			return nil
+		}
+		arm.Patterns = append(arm.Patterns, pattern)
+		if !p.isPeekToken(lexer.PIPE) {
+			break
+		}
+		p.nextToken() // Skip | token
+		p.nextToken()
+	}
+	if p.isPeekToken(lexer.IF) {
+		p.nextToken() // Skip if token
+		for !p.isPeekToken(lexer.ARROW) && !p.isPeekToken(lexer.EOF) {
+			p.nextToken()
+			arm.Guard = p.parseToken(arm.Guard)
+		}
+	}
+	if p.nextToken() != lexer.ARROW {
+		err := util.NewError(p.token, util.ExpectedArrow, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	if p.nextToken() == lexer.LBRACE {
+		body := p.newBlock()
+		if body == nil {
+			return nil
+		}
+		arm.Body = body
+	} else {
+		var exp Expression
+		for {
+			exp = p.parseToken(exp)
+			if p.isPeekToken(lexer.COMMA) || p.isPeekToken(lexer.RBRACE) || p.isPeekToken(lexer.EOF) {
+				break
+			}
+			p.nextToken()
+		}
+		if exp == nil {
+			return nil
+		}
+		arm.Body = exp
+	}
+	if p.isPeekToken(lexer.COMMA) {
+		p.nextToken() // Skip comma token
+	}
+	return arm
+}
+
+func (p *Parser) newPattern() Node {
+	switch p.token.Type {
+	case lexer.IDENT:
+		if p.isPeekToken(lexer.DOT) {
+			return p.newVariantPattern()
+		}
+		ident := p.newIdentifier().(*Identifier)
+		if ident.Value != "_" {
+			p.declare(ident, lexer.VARIABLE)
+		}
+		return ident
+	case lexer.INTEGER, lexer.STRING, lexer.TRUE, lexer.FALSE:
+		return p.parseToken(nil)
+	case lexer.MINUS:
+		if p.isPeekToken(lexer.INTEGER) {
+			return p.newPrefix()
+		}
+	case lexer.LBRACKET:
+		return p.newArrayPattern()
+	case lexer.LBRACE:
+		return p.newMapPattern()
+	}
+	err := util.NewError(p.token, util.ExpectedPattn, p.token.Literal)
+	p.errors.Add(err)
+	return nil
+}
+
+func (p *Parser) newArrayPattern() Node {
+	pattern := &ArrayPattern{Token: p.token}
+	p.nextToken() // Skip [ opening
+	for !p.isTokenOrEOF(lexer.RBRACKET) {
+		elem := p.newPattern()
+		if elem == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, elem)
+		if p.nextToken() == lexer.COMMA {
+			p.nextToken()
+		}
+	}
+	if p.isToken(lexer.EOF) {
+		err := util.NewError(p.token, util.UnexpectedEOF, "]")
+		p.errors.Add(err)
+		return nil
+	}
+	return pattern
+}
+
+func (p *Parser) newMapPattern() Node {
+	pattern := &MapPattern{Token: p.token}
+	p.nextToken() // Skip { opening
+	for !p.isTokenOrEOF(lexer.RBRACE) {
+		switch p.token.Type {
+		case lexer.INTEGER, lexer.STRING, lexer.TRUE, lexer.FALSE:
+			pattern.Keys = append(pattern.Keys, p.parseToken(nil))
+		default:
+			err := util.NewError(p.token, util.ExpectedPattn, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+		if p.nextToken() != lexer.COLON {
+			err := util.NewError(p.token, util.IllegalLetter, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+		p.nextToken()
+		value := p.newPattern()
+		if value == nil {
+			return nil
+		}
+		pattern.Values = append(pattern.Values, value)
+		if p.nextToken() == lexer.COMMA {
+			p.nextToken()
+		}
+	}
+	if p.isToken(lexer.EOF) {
+		err := util.NewError(p.token, util.UnexpectedEOF, "}")
+		p.errors.Add(err)
+		return nil
+	}
+	return pattern
+}
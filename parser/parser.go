@@ -9,6 +9,18 @@ type Parser struct {
 	lexer  *lexer.Lexer
 	token  lexer.Token
 	errors util.Errors
+	scopes []map[string]lexer.Type
+	loops  []string
+	label  string
+	blocks int
+	// Struct literals are not parsed where a block follows
+	noStruct bool
+	class    *Class
+	function *Function
+	// Enums declared, checked against the coverages found
+	enums     map[string]*Enum
+	coverages []*coverage
+	chaining  bool
 }
 
 func NewParser() *Parser {
@@ -25,12 +37,19 @@ func (p *Parser) ParsePackage(input, pkgName string) *Package {
 		node Node
 	)
 	p.lexer.UpdateInput([]byte(input))
+	p.scopes, p.loops, p.label, p.blocks = nil, nil, "", 0
+	p.noStruct, p.class, p.function = false, nil, nil
+	p.enums, p.coverages, p.chaining = make(map[string]*Enum), nil, false
+	p.pushScope()
 	for p.nextToken() != lexer.EOF {
 		node = p.parseStatement()
 		if node != nil { //TODO FIX NULL RETURNS AT { 5 + (a * 2) };
 			pkg.Nodes = append(pkg.Nodes, node)
 		}
 	}
+	for _, c := range p.coverages {
+		c.check(p.enums, &pkg.Warnings)
+	}
 	pkg.Errors = p.errors
 	p.errors.Clear()
 	return &pkg
@@ -40,18 +59,39 @@ func (p *Parser) parseStatement() Statement {
 	switch p.token.Type {
 	case lexer.VARIABLE:
 		return p.newVariable()
+	case lexer.CONSTANT:
+		return p.newConstant()
 	case lexer.LBRACE:
 		return p.newBlock()
 	case lexer.IF:
 		return p.newConditional()
+	case lexer.MATCH:
+		return p.newMatch()
+	case lexer.STRUCT:
+		return p.newStruct()
+	case lexer.CLASS:
+		return p.newClass()
+	case lexer.ENUM:
+		return p.newEnum()
 	case lexer.LOOP:
 		return p.newLoop()
+	case lexer.WHILE:
+		return p.newWhile()
+	case lexer.FOR:
+		return p.newFor()
 	case lexer.FUNCTION:
 		return p.newFunction()
 	case lexer.RETURN:
 		return p.newReturn()
-	case lexer.BREAK:
+	case lexer.YIELD:
+		return p.newYield()
+	case lexer.BREAK, lexer.CONTINUE:
 		return p.newBreak()
+	case lexer.IDENT:
+		if p.isPeekToken(lexer.COLON) {
+			return p.newLabeled()
+		}
+		return p.parseExpression()
 	default:
 		return p.parseExpression()
 	}
@@ -61,6 +101,10 @@ func (p *Parser) parseExpression() Expression {
 	var exp Expression
 	for !p.isTokenOrEOF(lexer.SEMICOLON, lexer.RBRACE) {
 		exp = p.parseToken(exp)
+		// The last expression of a block may omit its semicolon
+		if p.blocks > 0 && exp != nil && p.isPeekToken(lexer.RBRACE) {
+			return exp
+		}
 		p.nextToken()
 	}
 	if p.isToken(lexer.EOF) {
@@ -98,7 +142,15 @@ func (p *Parser) parseToken(exp Expression) Expression { // TODO ADD LPAREN OBJ
 		if p.isPeekToken(lexer.LPAREN) {
 			p.nextToken() // Skip current ident token
 			exp = p.newFuncCall(exp)
+		} else if p.isPeekToken(lexer.LBRACE) && !p.noStruct {
+			p.nextToken() // Skip current ident token
+			exp = p.newStructLiteral(exp)
 		}
+		exp = p.parsePostfix(exp)
+	case lexer.SELF:
+		exp = p.parsePostfix(p.newSelf())
+	case lexer.SUPER:
+		exp = p.parsePostfix(p.newSuper())
 	case lexer.TRUE, lexer.FALSE:
 		exp = p.newBoolean()
 	case lexer.INTEGER:
@@ -116,13 +168,24 @@ func (p *Parser) parseToken(exp Expression) Expression { // TODO ADD LPAREN OBJ
 		}
 	case lexer.ASSIGN:
 		switch exp.(type) {
-		case *Variable, *Identifier:
+		case *Variable, *Constant, *Identifier, *Field:
 			exp = p.newAssign(exp)
 		default:
 			err := util.NewError(p.token, util.IllegalOpeAtt)
 			p.errors.Add(err)
 			return nil
 		}
+	case lexer.PLUSASSIGN, lexer.MINUSASSIGN, lexer.ASTERISKASSIGN, lexer.SLASHASSIGN:
+		fallthrough
+	case lexer.INCREMENT, lexer.DECREMENT:
+		switch exp.(type) {
+		case *Identifier, *Field:
+			exp = p.newUpdate(exp)
+		default:
+			err := util.NewError(p.token, util.IllegalOpeAtt)
+			p.errors.Add(err)
+			return nil
+		}
 	case lexer.EQ, lexer.NOTEQ, lexer.LT:
 		fallthrough
 	case lexer.LTEQ, lexer.GT, lexer.GTEQ:
@@ -130,7 +193,24 @@ func (p *Parser) parseToken(exp Expression) Expression { // TODO ADD LPAREN OBJ
 	case lexer.ASTERISK, lexer.SLASH: //TODO ADD OP PRIORITY
 		exp = p.newInfix(exp)
 	case lexer.LPAREN:
-		exp = p.parseGroupExpression()
+		exp = p.parsePostfix(p.checkOperand(p.parseGroupExpression()))
+	case lexer.IF:
+		if exp != nil {
+			err := util.NewError(p.token, util.IllegalLetter, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+		if cond := p.newConditional(); cond != nil {
+			cond.(*Conditional).Valued = true
+			exp = cond
+		}
+	case lexer.MATCH:
+		if exp != nil {
+			err := util.NewError(p.token, util.IllegalLetter, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+		exp = p.newMatch()
 	default:
 		err := util.NewError(p.token, util.IllegalLetter, p.token.Literal)
 		p.errors.Add(err)
@@ -167,3 +247,56 @@ func (p *Parser) isTokenOrEOF(t ...lexer.Type) bool {
 	}
 	return false
 }
+
+func (p *Parser) pushScope() {
+	p.scopes = append(p.scopes, make(map[string]lexer.Type))
+}
+
+func (p *Parser) popScope() {
+	p.scopes = p.scopes[:len(p.scopes)-1]
+}
+
+// declare records the keyword a name is declared with in the
+// current scope, failing when it redeclares a constant. Only
+// functions, structs, classes and enums may be declared again
+// over themselves.
+func (p *Parser) declare(ident *Identifier, kind lexer.Type) {
+	scope := p.scopes[len(p.scopes)-1]
+	switch previous := scope[ident.Value]; {
+	case previous == lexer.CONSTANT, previous != 0 && previous != lexer.VARIABLE && previous != kind:
+		err := util.NewError(ident.Token, util.DuplicateDecl, ident.Value)
+		p.errors.Add(err)
+	}
+	scope[ident.Value] = kind
+}
+
+// declaredAs returns the keyword the closest known declaration
+// of a name used, or 0 when it can't be known before running.
+func (p *Parser) declaredAs(name string) lexer.Type {
+	for i := len(p.scopes) - 1; i >= 0 && p.scopes[i] != nil; i-- {
+		if kind, ok := p.scopes[i][name]; ok {
+			return kind
+		}
+	}
+	return 0
+}
+
+// newLoopBody parses the body of a loop, labeled after the
+// label being parsed if any.
+func (p *Parser) newLoopBody() *Block {
+	p.loops = append(p.loops, p.label)
+	p.label = ""
+	defer func() { p.loops = p.loops[:len(p.loops)-1] }()
+	return p.newBlock()
+}
+
+// hasLoop tells whether the statement being parsed is within
+// a loop, labeled as given unless empty.
+func (p *Parser) hasLoop(label string) bool {
+	for i := len(p.loops) - 1; i >= 0; i-- {
+		if label == "" || p.loops[i] == label {
+			return true
+		}
+	}
+	return false
+}
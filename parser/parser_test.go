@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"fmt"
 	"testing"
 )
 
@@ -30,8 +29,33 @@ func TestParseNodes(t *testing.T) {
 		"j(5, (1 + (-1)))",
 		"(5 + (-j(a, b, c)))",
 		"return j()",
-		"break",
+		"outer: loop {\n\tbreak outer;\n}",
+		"while (true) {\n\tcontinue;\n}",
+		"if (a) {\n\t1;\n} else if (b) {\n\t2;\n} else {\n\t3;\n}",
+		"var x = if (a) {\n\t1;\n} else {\n\t(2 + b);\n}",
+		"match (a) {\n\t1 | (-2) => \"small\",\n\t[x, _] if (x > 0) => x,\n\t{\"k\": [y]} => {\n\ty;\n},\n\tz => (z + 1),\n}",
 		"{\n\t(5 + (a * 2));\n}",
+		"const k = (a + 1)",
+		"a += (b * 2)",
+		"a++",
+		"a--",
+		"while ((a < 3)) {\n\ta++;\n}",
+		"for (var i = 0; (i < 3); i++) {\n\t\"hello\";\n}",
+		"for (; ; ) {\n\tbreak;\n}",
+		"for c in \"abc\" {\n\tc;\n}",
+		"for i, x in range(0, (a + 1)) {\n\tx;\n}",
+		"struct Point { x, y }",
+		"var p = Point { x: 1, y: (a + 2) }",
+		"(a + p.x)",
+		"p.x = (p.y + 1)",
+		"p.x++",
+		"for q in points {\n\tq.x;\n}",
+		"for q in match (a) {\n\t_ => Point { x: 1 },\n} {\n\tq;\n}",
+		"class Dog : Animal {\n\tfun init(name) {\n\tsuper.init(name);\n}\n\tfun speak() {\n\treturn (self.name + \"!\");\n}\n}",
+		"(Dog(\"Rex\").speak() + p.x.y(1, 2))",
+		"enum Result { Ok(value), Err(error), Pending }",
+		"match (r) {\n\tResult.Ok(v) | Result.Err(v) => v,\n\tResult.Pending => Color.Red,\n}",
+		"fun gen(n) {\n\tyield (n + 1);\n\tyield;\n}",
 	}
 	var code = `
 1; 
@@ -57,17 +81,180 @@ a == b;
 j(5, 1 + -1);
 5 + -j(a, b, c);
 return j();
-break;
+outer: loop { break outer; }
+while (true) { continue; }
+if (a) { 1; } else if (b) { 2; } else { 3; }
+var x = if (a) { 1 } else { 2 + b };
+match (a) {
+	1 | -2 => "small",
+	[x, _] if x > 0 => x,
+	{"k": [y]} => { y },
+	z => z + 1
+}
 { 5 + (a * 2); }
+const k = a + 1;
+a += b * 2;
+a++;
+a--;
+while (a < 3) { a++; }
+for (var i = 0; i < 3; i++) { "hello"; }
+for (;;) { break; }
+for c in "abc" { c; }
+for i, x in range(0, a + 1) { x; }
+struct Point { x, y }
+var p = Point { x: 1, y: a + 2 };
+a + p.x;
+p.x = p.y + 1;
+p.x++;
+for q in points { q.x; }
+for q in match (a) { _ => Point { x: 1 } } { q; }
+class Dog : Animal {
+	fun init(name) { super.init(name); }
+	fun speak() { return self.name + "!"; }
+}
+Dog("Rex").speak() + p.x.y(1, 2);
+enum Result { Ok(value), Err(error), Pending }
+match (r) { Result.Ok(v) | Result.Err(v) => v, Result.Pending => Color.Red }
+fun gen(n) { yield n + 1; yield; }
 `
 	p := NewParser()
 	program := p.ParsePackage(code, "test")
-	fmt.Println(program.Errors.String())
+	if program.Errors.Len() > 0 {
+		t.Fatalf("unexpected parse errors\n%s", program.Errors.String())
+	}
+	if len(program.Nodes) != len(test) {
+		t.Fatalf("expected %d nodes got %d", len(test), len(program.Nodes))
+	}
 	for i, line := range program.Nodes {
 		if line.String() != test[i] {
 			t.Fatalf("failed at line %d expected \"%s\" got \"%s\"",
 				i, test[i], line.String())
 		}
 	}
-	fmt.Print(program.String())
+}
+
+func TestParseErrors(t *testing.T) {
+	var tests = []struct {
+		code string
+		err  string
+	}{
+		{"const a;", "* Error at L1 expected value for constant \"a\""},
+		{"const a = 1; a = 2;", "* Error at L1 cannot assign to constant \"a\""},
+		{"const a = 1; { a = 2; }", "* Error at L1 cannot assign to constant \"a\""},
+		{"const a = 1; var a = 2;", "* Error at L1 \"a\" is already declared"},
+		{"fun f() { } f = 1;", "* Error at L1 cannot assign to constant \"f\""},
+		{"const a = 1; { var a = 2; a = 3; }", ""},
+		{"const a = 1; fun f(a) { a = 2; }", ""},
+		{"fun f() { } fun f() { }", ""},
+		{"const a = 1; a += 1;", "* Error at L1 cannot assign to constant \"a\""},
+		{"const a = 1; a++;", "* Error at L1 cannot assign to constant \"a\""},
+		{"1++;", "* Error at L1 illegal operation attempt"},
+		{"var i = 0; var j = i++;", "* Error at L1 illegal operation attempt"},
+		{"var i = 0; print(i++);", "* Error at L1 illegal operation attempt"},
+		{"var x = 0; var y = 0; x = y += 1;", "* Error at L1 illegal operation attempt"},
+		{"var i = 0; var j = (i += 1) + 1;", "* Error at L1 illegal operation attempt"},
+		{"fun f(i) { return i++; }", "* Error at L1 illegal operation attempt"},
+		{"const i = 0; for (var i = 0; i < 3; i += 1) { }", ""},
+		{"for (const i = 0; i < 3; i++) { }", "* Error at L1 cannot assign to constant \"i\""},
+		{"for (var i = 0) { }", "* Error at L1 expected \";\" but got \")\""},
+		{"for x of y { }", "* Error at L1 expected \"in\" but got \"of\""},
+		{"for x in { }", "* Error at L1 expected opening brace but got \"{\""},
+		{"break;", "* Error at L1 break outside of a loop"},
+		{"if (a) { } else while (b) { }", "* Error at L1 expected opening brace but got \"while\""},
+		{"var x = 1 if (a) { 1 };", "* Error at L1 illegal character \"if\""},
+		{"1 }", "* Error at L1 unexpected right brace, expected \";\""},
+		{"match (a) { 1 -> 2 }", "* Error at L1 expected \"=>\" but got \"-\""},
+		{"match (a) { (1) => 2 }", "* Error at L1 expected pattern but got \"(\""},
+		{"match (a) { {k: 1} => 2 }", "* Error at L1 expected pattern but got \"k\""},
+		{"const x = 1; match (a) { x => x = 2 }", ""},
+		{"loop { fun f() { continue; } }", "* Error at L1 continue outside of a loop"},
+		{"a: loop { loop { break b; } }", "* Error at L1 label \"b\" not found"},
+		{"a: loop { } loop { continue a; }", "* Error at L1 label \"a\" not found"},
+		{"a: var x;", "* Error at L1 expected loop after label \"a\""},
+		{"outer: loop { outer: loop { break outer; } }", "* Error at L1 label \"outer\" is already used by an enclosing loop"},
+		{"a: loop { b: loop { a: while (true) { } } }", "* Error at L1 label \"a\" is already used by an enclosing loop"},
+		{"a: loop { } a: loop { fun f() { a: loop { } } }", ""},
+		{"a: for x in range(0, 3) { b: while (true) { continue a; break b; } }", ""},
+		{"struct P { x, x }", "* Error at L1 \"x\" is already declared"},
+		{"struct P { x } P = 1;", "* Error at L1 cannot assign to constant \"P\""},
+		{"struct P { x } struct P { x, y }", ""},
+		{"struct P { x } var P;", "* Error at L1 \"P\" is already declared"},
+		{"P { x = 1 };", "* Error at L1 illegal character \"=\""},
+		{"p.1;", "* Error at L1 expected name declaration but got \"1\""},
+		{"class A { var x; }", "* Error at L1 expected method but got \"var\""},
+		{"self.x = 1;", "* Error at L1 self outside of a class"},
+		{"fun f() { super.f(); }", "* Error at L1 super outside of a class"},
+		{"class A { fun f() { super.f(); } }", "* Error at L1 class \"A\" has no parent"},
+		{"class A : B { fun f() { super; } }", "* Error at L1 expected \".\" but got \";\""},
+		{"class A { fun f() { self = 1; } }", "* Error at L1 illegal operation attempt"},
+		{"class A { fun f() { } } f();", ""},
+		{"class A { } A = 1;", "* Error at L1 cannot assign to constant \"A\""},
+		{"enum E { A, A }", "* Error at L1 \"A\" is already declared"},
+		{"enum E { A(1) }", "* Error at L1 expected name declaration but got \"1\""},
+		{"enum E { A } E = 1;", "* Error at L1 cannot assign to constant \"E\""},
+		{"enum E { A } enum E { B }", ""},
+		{"match (a) { E.(x) => 1 }", "* Error at L1 expected name declaration but got \"(\""},
+		{"yield 1;", "* Error at L1 yield outside of a function"},
+		{"fun f() { fun g() { yield 1; } } loop { yield 2; }", "* Error at L1 yield outside of a function"},
+	}
+	for _, test := range tests {
+		program := NewParser().ParsePackage(test.code, "test")
+		// Only the first error matters, the rest being cascades
+		err := ""
+		if program.Errors.Len() > 0 {
+			err = program.Errors[0].String()
+		}
+		if err != test.err {
+			t.Fatalf("%s expected %q got %q", test.code, test.err, program.Errors.String())
+		}
+	}
+}
+
+func TestParseGenerators(t *testing.T) {
+	program := NewParser().ParsePackage("fun f() { fun g() { yield 1; } } fun h() { loop { yield 2; } }", "test")
+	if program.Errors.Len() > 0 {
+		t.Fatalf("unexpected parse errors\n%s", program.Errors.String())
+	}
+	f, h := program.Nodes[0].(*Function), program.Nodes[1].(*Function)
+	g := f.Body.Nodes[0].(*Function)
+	if f.Generator || !g.Generator || !h.Generator {
+		t.Fatalf("wrong generators f=%t g=%t h=%t", f.Generator, g.Generator, h.Generator)
+	}
+}
+
+func TestCoverageWarnings(t *testing.T) {
+	var enums = "enum Color { Red, Green, Blue }\nenum Shape { Dot, Line(n) }\n"
+	var tests = []struct {
+		code    string
+		warning string
+	}{
+		{`match (c) { Color.Red => 1, Color.Green => 2, Color.Blue => 3 }`, ""},
+		{`match (c) { Color.Red | Color.Blue => 1 }`, "match over Color misses Color.Green"},
+		{`match (c) { Color.Red => 1, _ => 2 }`, ""},
+		{`match (c) { Color.Red => 1, Color.Green if a => 2 }`, "match over Color misses Color.Green, Color.Blue"},
+		{`match (s) { Shape.Dot => 1, Shape.Line(1) => 2 }`, "match over Shape misses Shape.Line"},
+		{`match (s) { Shape.Dot => 1, Shape.Line(n) => n }`, ""},
+		{`match (c) { 1 => 1 }`, ""},
+		{`if (c == Color.Red) { } else if (Color.Green == c) { }`, "if over Color misses Color.Blue"},
+		{`if (c == Color.Red) { } else if (c == Color.Green) { } else { }`, ""},
+		{`if (c == Color.Red) { } else if (d == Color.Green) { }`, ""},
+		{`if (c == Color.Red) { }`, ""},
+		{`if (p.x == 1) { } else if (p.x == 2) { }`, ""},
+		{`if (p.a.b == 1) { } else if (p.a.b == Color.Red) { }`, ""},
+		{`class A { fun f() { if (self.x == 1) { } else if (self.x == 2) { } } }`, ""},
+		{`fun f(c) { return match (c) { Color.Red => 1 }; }`, "match over Color misses Color.Green, Color.Blue"},
+	}
+	for _, test := range tests {
+		pkg := NewParser().ParsePackage(enums+test.code, "test")
+		if pkg.Errors.Len() > 0 {
+			t.Fatalf("%s: unexpected parse errors\n%s", test.code, pkg.Errors.String())
+		}
+		warning := ""
+		if pkg.Warnings.Len() > 0 {
+			warning = pkg.Warnings[0].Message()
+		}
+		if pkg.Warnings.Len() > 1 || warning != test.warning {
+			t.Fatalf("%s: expected warning %q got %q", test.code, test.warning, pkg.Warnings.String())
+		}
+	}
 }
@@ -29,6 +29,7 @@ func (p *Parser) newVariable() Expression {
 		return nil
 	}
 	def.Name = p.newIdentifier().(*Identifier)
+	p.declare(def.Name, lexer.VARIABLE)
 
 	if p.nextToken() != lexer.SEMICOLON {
 		return p.parseToken(def)
@@ -36,8 +37,41 @@ func (p *Parser) newVariable() Expression {
 	return def
 }
 
+type Constant struct {
+	Token lexer.Token
+	Name  *Identifier
+}
+
+func (c *Constant) Literal() string { return c.Name.String() }
+func (c *Constant) String() string {
+	var out bytes.Buffer
+	out.WriteString(c.Token.Literal + " ")
+	out.WriteString(c.Name.String())
+	return out.String()
+}
+
+func (p *Parser) newConstant() Expression {
+	def := &Constant{Token: p.token}
+
+	if p.nextToken() != lexer.IDENT {
+		err := util.NewError(p.token, util.ExpectedIdent, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	def.Name = p.newIdentifier().(*Identifier)
+	p.declare(def.Name, lexer.CONSTANT)
+
+	if p.nextToken() != lexer.ASSIGN {
+		err := util.NewError(p.token, util.ExpectedValue, def.Name.Value)
+		p.errors.Add(err)
+		return nil
+	}
+	return p.parseToken(def)
+}
+
 type Block struct {
 	Token lexer.Token
+	End   lexer.Token
 	Nodes []Node
 }
 
@@ -54,6 +88,12 @@ func (b *Block) String() string {
 
 func (p *Parser) newBlock() *Block {
 	block := &Block{Token: p.token}
+	p.pushScope()
+	p.blocks++
+	defer func() {
+		p.popScope()
+		p.blocks--
+	}()
 	p.nextToken() // Skip { opening
 	for !p.isTokenOrEOF(lexer.RBRACE) {
 		node := p.parseStatement()
@@ -67,14 +107,19 @@ func (p *Parser) newBlock() *Block {
 		p.errors.Add(err)
 		return nil
 	}
+	block.End = p.token
 	return block
 }
 
+// Conditional is also an expression, valued as the block
+// chosen. Else is either a block or another conditional, and
+// Valued is set when it's in expression position.
 type Conditional struct {
 	Token   lexer.Token
 	Require Expression
 	To      *Block
-	Else    *Block
+	Else    Statement
+	Valued  bool
 }
 
 func (c *Conditional) Literal() string { return c.Token.Literal }
@@ -95,6 +140,12 @@ func (c *Conditional) String() string {
 
 func (p *Parser) newConditional() Statement {
 	cond := &Conditional{Token: p.token}
+	// Struct literals are unambiguous within its own braces
+	noStruct := p.noStruct
+	p.noStruct = false
+	defer func() { p.noStruct = noStruct }()
+	chained := p.chaining
+	p.chaining = false
 
 	if p.nextToken() != lexer.LPAREN {
 		err := util.NewError(p.token, util.ExpectedParen, p.token.Literal)
@@ -112,12 +163,29 @@ func (p *Parser) newConditional() Statement {
 
 	if p.isPeekToken(lexer.ELSE) {
 		p.nextToken() // Skip else token
-		if p.nextToken() != lexer.LBRACE {
-			err := util.NewError(p.token, util.ExpectedBrace, p.token.Literal)
-			p.errors.Add(err)
-			return nil
+		if p.nextToken() == lexer.IF {
+			p.chaining = true
+			chain := p.newConditional()
+			if chain == nil {
+				return nil
+			}
+			cond.Else = chain
+		} else {
+			if !p.isToken(lexer.LBRACE) {
+				err := util.NewError(p.token, util.ExpectedBrace, p.token.Literal)
+				p.errors.Add(err)
+				return nil
+			}
+			block := p.newBlock()
+			if block == nil {
+				return nil
+			}
+			cond.Else = block
 		}
-		cond.Else = p.newBlock()
+	}
+	// Chains are covered from their first if
+	if c := p.ifCoverage(cond); c != nil && !chained {
+		p.coverages = append(p.coverages, c)
 	}
 	return cond
 }
@@ -142,15 +210,230 @@ func (p *Parser) newLoop() Statement {
 		p.errors.Add(err)
 		return nil
 	}
-	while.Body = p.newBlock()
+	if while.Body = p.newLoopBody(); while.Body == nil {
+		return nil
+	}
+	return while
+}
+
+type While struct {
+	Token   lexer.Token
+	Require Expression
+	Body    *Block
+}
+
+func (w *While) Literal() string { return w.Token.Literal }
+func (w *While) String() string {
+	var out bytes.Buffer
+	out.WriteString("while (")
+	out.WriteString(w.Require.String())
+	out.WriteString(") ")
+	out.WriteString(w.Body.String())
+	return out.String()
+}
+
+func (p *Parser) newWhile() Statement {
+	while := &While{Token: p.token}
+
+	if p.nextToken() != lexer.LPAREN {
+		err := util.NewError(p.token, util.ExpectedParen, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	while.Require = p.parseGroupExpression()
+
+	if p.nextToken() != lexer.LBRACE {
+		err := util.NewError(p.token, util.ExpectedBrace, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	if while.Body = p.newLoopBody(); while.Body == nil {
+		return nil
+	}
 	return while
 }
 
+// For has every clause optional, a missing Require repeating
+// the body until a break or a return.
+type For struct {
+	Token   lexer.Token
+	Init    Statement
+	Require Expression
+	Post    Expression
+	Body    *Block
+}
+
+func (f *For) Literal() string { return f.Token.Literal }
+func (f *For) String() string {
+	var out bytes.Buffer
+	out.WriteString("for (")
+	for i, clause := range []Node{f.Init, f.Require, f.Post} {
+		if i > 0 {
+			out.WriteString("; ")
+		}
+		if clause != nil {
+			out.WriteString(clause.String())
+		}
+	}
+	out.WriteString(") ")
+	out.WriteString(f.Body.String())
+	return out.String()
+}
+
+func (p *Parser) newFor() Statement {
+	// Variables declared by the loop belong to it
+	p.pushScope()
+	defer p.popScope()
+	if p.isPeekToken(lexer.IDENT) {
+		return p.newForIn()
+	}
+	loop := &For{Token: p.token}
+
+	if p.nextToken() != lexer.LPAREN {
+		err := util.NewError(p.token, util.ExpectedParen, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	if p.nextToken() != lexer.SEMICOLON {
+		loop.Init = p.parseStatement()
+		// Declarations stop before their semicolon
+		if !p.isToken(lexer.SEMICOLON) && p.nextToken() != lexer.SEMICOLON {
+			err := util.NewError(p.token, util.ExpectedSemic, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+	}
+	if p.nextToken() != lexer.SEMICOLON {
+		loop.Require = p.parseExpression()
+		if loop.Require == nil {
+			return nil
+		}
+	}
+	loop.Post = p.parseGroupExpression()
+
+	if p.nextToken() != lexer.LBRACE {
+		err := util.NewError(p.token, util.ExpectedBrace, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	if loop.Body = p.newLoopBody(); loop.Body == nil {
+		return nil
+	}
+	return loop
+}
+
+// ForIn binds Value to every element of Iterable, and Key,
+// when given, to its position or its key for maps.
+type ForIn struct {
+	Token    lexer.Token
+	Key      *Identifier
+	Value    *Identifier
+	Iterable Expression
+	Body     *Block
+}
+
+func (f *ForIn) Literal() string { return f.Token.Literal }
+func (f *ForIn) String() string {
+	var out bytes.Buffer
+	out.WriteString("for ")
+	if f.Key != nil {
+		out.WriteString(f.Key.String() + ", ")
+	}
+	out.WriteString(f.Value.String())
+	out.WriteString(" in ")
+	out.WriteString(f.Iterable.String())
+	out.WriteString(" ")
+	out.WriteString(f.Body.String())
+	return out.String()
+}
+
+func (p *Parser) newForIn() Statement {
+	loop := &ForIn{Token: p.token}
+	p.nextToken()
+	loop.Value = p.newIdentifier().(*Identifier)
+	if p.isPeekToken(lexer.COMMA) {
+		p.nextToken() // Skip comma token
+		if p.nextToken() != lexer.IDENT {
+			err := util.NewError(p.token, util.ExpectedIdent, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+		loop.Key, loop.Value = loop.Value, p.newIdentifier().(*Identifier)
+		p.declare(loop.Key, lexer.VARIABLE)
+	}
+	p.declare(loop.Value, lexer.VARIABLE)
+
+	if p.nextToken() != lexer.IN {
+		err := util.NewError(p.token, util.ExpectedInKey, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	noStruct := p.noStruct
+	p.noStruct = true
+	for p.nextToken() != lexer.LBRACE && !p.isToken(lexer.EOF) {
+		loop.Iterable = p.parseToken(loop.Iterable)
+	}
+	p.noStruct = noStruct
+	if loop.Iterable == nil || !p.isToken(lexer.LBRACE) {
+		err := util.NewError(p.token, util.ExpectedBrace, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	if loop.Body = p.newLoopBody(); loop.Body == nil {
+		return nil
+	}
+	return loop
+}
+
+// Labeled names a loop for the break and continue statements
+// within it to target.
+type Labeled struct {
+	Token lexer.Token
+	Label *Identifier
+	Loop  Statement
+}
+
+func (l *Labeled) Literal() string { return l.Token.Literal }
+func (l *Labeled) String() string {
+	var out bytes.Buffer
+	out.WriteString(l.Label.String() + ": ")
+	out.WriteString(l.Loop.String())
+	return out.String()
+}
+
+func (p *Parser) newLabeled() Statement {
+	labeled := &Labeled{Token: p.token}
+	labeled.Label = p.newIdentifier().(*Identifier)
+	// An enclosing loop with the same label would be shadowed
+	if p.hasLoop(labeled.Label.Value) {
+		err := util.NewError(labeled.Label.Token, util.LabelReplaced, labeled.Label.Value)
+		p.errors.Add(err)
+	}
+	p.nextToken() // Skip colon token
+	switch p.nextToken() {
+	case lexer.LOOP, lexer.WHILE, lexer.FOR:
+	default:
+		err := util.NewError(p.token, util.ExpectedLoopL, labeled.Label.Value)
+		p.errors.Add(err)
+		return nil
+	}
+	p.label = labeled.Label.Value
+	labeled.Loop = p.parseStatement()
+	p.label = ""
+	if labeled.Loop == nil {
+		return nil
+	}
+	return labeled
+}
+
+// Function is a generator when its body, without the one of
+// the functions in it, yields.
 type Function struct {
-	Token  lexer.Token
-	Name   *Identifier
-	Params []*Identifier
-	Body   *Block
+	Token     lexer.Token
+	Name      *Identifier
+	Params    []*Identifier
+	Body      *Block
+	Generator bool
 }
 
 func (f *Function) Literal() string { return f.Token.Literal }
@@ -178,12 +461,24 @@ func (p *Parser) newFunction() Statement {
 		return nil
 	}
 	fun.Name = p.newIdentifier().(*Identifier)
+	p.declare(fun.Name, lexer.FUNCTION)
 	p.nextToken()
 	if !p.isToken(lexer.LPAREN) {
 		err := util.NewError(p.token, util.ExpectedParen, p.token.Literal)
 		p.errors.Add(err)
 		return nil
 	}
+	// Names of callers may shadow the ones around the function,
+	// so its body doesn't see them when checking assignments.
+	// Neither can it break out of the loops around it.
+	p.scopes = append(p.scopes, nil)
+	p.pushScope()
+	loops, function := p.loops, p.function
+	p.loops, p.function = nil, fun
+	defer func() {
+		p.scopes = p.scopes[:len(p.scopes)-2]
+		p.loops, p.function = loops, function
+	}()
 	params := p.newParameters()
 	for _, param := range params {
 		ident, valid := param.(*Identifier)
@@ -192,6 +487,7 @@ func (p *Parser) newFunction() Statement {
 			p.errors.Add(err)
 			return nil
 		}
+		p.declare(ident, lexer.VARIABLE)
 		fun.Params = append(fun.Params, ident)
 	}
 	if p.nextToken() != lexer.LBRACE {
@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"bytes"
+	"github.com/Onelio/Eldrlang/lexer"
+	"github.com/Onelio/Eldrlang/util"
+	"strings"
+)
+
+type Struct struct {
+	Token  lexer.Token
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (s *Struct) Literal() string { return s.Token.Literal }
+func (s *Struct) String() string {
+	var out bytes.Buffer
+	var fields []string
+	for _, field := range s.Fields {
+		fields = append(fields, field.String())
+	}
+	out.WriteString("struct ")
+	out.WriteString(s.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(" }")
+	return out.String()
+}
+
+func (p *Parser) newStruct() Statement {
+	def := &Struct{Token: p.token}
+
+	if p.nextToken() != lexer.IDENT {
+		err := util.NewError(p.token, util.ExpectedIdent, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	def.Name = p.newIdentifier().(*Identifier)
+	p.declare(def.Name, lexer.STRUCT)
+
+	if p.nextToken() != lexer.LBRACE {
+		err := util.NewError(p.token, util.ExpectedBrace, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	seen := make(map[string]bool)
+	for p.nextToken() != lexer.RBRACE {
+		if !p.isToken(lexer.IDENT) {
+			err := util.NewError(p.token, util.ExpectedIdent, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+		field := p.newIdentifier().(*Identifier)
+		if seen[field.Value] {
+			err := util.NewError(field.Token, util.DuplicateDecl, field.Value)
+			p.errors.Add(err)
+		}
+		seen[field.Value] = true
+		def.Fields = append(def.Fields, field)
+		if p.isPeekToken(lexer.COMMA) {
+			p.nextToken() // Skip comma token
+		}
+	}
+	return def
+}
+
+// StructLiteral builds a struct of the type named, the fields
+// not given being null.
+type StructLiteral struct {
+	Token  lexer.Token
+	Name   *Identifier
+	Fields []*Identifier
+	Values []Expression
+}
+
+func (s *StructLiteral) Literal() string { return s.Token.Literal }
+func (s *StructLiteral) String() string {
+	var out bytes.Buffer
+	var fields []string
+	for i, field := range s.Fields {
+		fields = append(fields, field.String()+": "+s.Values[i].String())
+	}
+	out.WriteString(s.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(" }")
+	return out.String()
+}
+
+func (p *Parser) newStructLiteral(name Expression) Expression {
+	literal := &StructLiteral{Token: p.token, Name: name.(*Identifier)}
+	for p.nextToken() != lexer.RBRACE {
+		if !p.isToken(lexer.IDENT) {
+			err := util.NewError(p.token, util.ExpectedIdent, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+		field := p.newIdentifier().(*Identifier)
+		if p.nextToken() != lexer.COLON {
+			err := util.NewError(p.token, util.IllegalLetter, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+		value := p.checkOperand(p.parseOperand())
+		if value == nil {
+			err := util.NewError(p.token, util.IllegalLetter, p.token.Literal)
+			p.errors.Add(err)
+			return nil
+		}
+		literal.Fields = append(literal.Fields, field)
+		literal.Values = append(literal.Values, value)
+		if p.isPeekToken(lexer.COMMA) {
+			p.nextToken() // Skip comma token
+		}
+	}
+	return literal
+}
+
+type Field struct {
+	Token  lexer.Token
+	Object Expression
+	Name   *Identifier
+}
+
+func (f *Field) Literal() string { return f.Token.Literal }
+func (f *Field) String() string {
+	return f.Object.String() + "." + f.Name.String()
+}
+
+func (p *Parser) newField(object Expression) Expression {
+	field := &Field{Token: p.token, Object: object}
+	if object == nil {
+		err := util.NewError(p.token, util.IllegalOpeAtt)
+		p.errors.Add(err)
+		return nil
+	}
+	if p.nextToken() != lexer.IDENT {
+		err := util.NewError(p.token, util.ExpectedIdent, p.token.Literal)
+		p.errors.Add(err)
+		return nil
+	}
+	field.Name = p.newIdentifier().(*Identifier)
+	if p.isPeekToken(lexer.LPAREN) {
+		p.nextToken() // Skip field name
+		return p.newFuncCall(field)
+	}
+	return field
+}
+
+// parsePostfix parses the field accesses and method calls
+// following an operand, binding them tighter than any operator.
+func (p *Parser) parsePostfix(exp Expression) Expression {
+	for exp != nil && p.isPeekToken(lexer.DOT) {
+		p.nextToken() // Skip operand token
+		exp = p.newField(exp)
+	}
+	return exp
+}
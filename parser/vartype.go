@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"github.com/Onelio/Eldrlang/lexer"
 	"github.com/Onelio/Eldrlang/util"
+	"math/big"
 	"strconv"
 )
 
@@ -56,9 +57,11 @@ func (p *Parser) newBoolean() Expression {
 	return &Boolean{Token: p.token, Value: p.isToken(lexer.TRUE)}
 }
 
+// Integer holds literals out of the int64 range in Big.
 type Integer struct {
 	Token lexer.Token
 	Value int64
+	Big   *big.Int
 }
 
 func (i *Integer) Literal() string { return i.Token.Literal }
@@ -68,13 +71,16 @@ func (p *Parser) newInteger() Expression {
 	integer := &Integer{Token: p.token}
 
 	value, err := strconv.ParseInt(p.token.Literal, 0, 64)
-	if err != nil {
-		err := util.NewError(p.token, util.InvalidNumber, p.token.Literal)
-		p.errors.Add(err)
-		return nil
+	if err == nil {
+		integer.Value = value
+		return integer
+	}
+	if huge, ok := new(big.Int).SetString(p.token.Literal, 0); ok {
+		integer.Big = huge
+		return integer
 	}
-	integer.Value = value
-	return integer
+	p.errors.Add(util.NewError(p.token, util.InvalidNumber, p.token.Literal))
+	return nil
 }
 
 type String struct {
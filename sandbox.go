@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"github.com/Onelio/Eldrlang/object"
+	"strings"
+)
+
+type pathList []string
+
+func (l *pathList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *pathList) Set(value string) error {
+	*l = append(*l, strings.Split(value, ",")...)
+	return nil
+}
+
+type sandboxFlags struct {
+	enabled bool
+	allowed object.Policy
+}
+
+func newSandboxFlags(flags *flag.FlagSet) *sandboxFlags {
+	s := &sandboxFlags{}
+	flags.BoolVar(&s.enabled, "sandbox", false, "deny every capability not explicitly allowed")
+	flags.Var((*pathList)(&s.allowed.Read), "allow-read", "allow reading files under the given paths")
+	flags.Var((*pathList)(&s.allowed.Write), "allow-write", "allow writing files under the given paths")
+	flags.BoolVar(&s.allowed.Stdin, "allow-stdin", false, "allow reading the standard input")
+	flags.BoolVar(&s.allowed.Stdout, "allow-stdout", false, "allow writing the standard output")
+	flags.BoolVar(&s.allowed.Stderr, "allow-stderr", false, "allow writing the standard error")
+	flags.BoolVar(&s.allowed.Exec, "allow-exec", false, "allow running processes")
+	flags.BoolVar(&s.allowed.Net, "allow-net", false, "allow network access")
+	return s
+}
+
+// policy returns nil, granting every capability, unless the
+// sandbox was enabled either explicitly or by an allow flag.
+func (s *sandboxFlags) policy(flags *flag.FlagSet) *object.Policy {
+	sandboxed := s.enabled
+	flags.Visit(func(f *flag.Flag) {
+		if strings.HasPrefix(f.Name, "allow-") {
+			sandboxed = true
+		}
+	})
+	if !sandboxed {
+		return nil
+	}
+	policy := s.allowed
+	return &policy
+}
@@ -0,0 +1,83 @@
+package tester
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Onelio/Eldrlang/evaluator"
+	"github.com/Onelio/Eldrlang/object"
+	"strings"
+)
+
+func registerAsserts(eval *evaluator.Evaluator) {
+	message := object.Param{Name: "message", Types: []object.Type{object.STRING}, Optional: true}
+	builtins := []*object.Builtin{
+		{
+			Name: "assert",
+			Params: []object.Param{
+				{Name: "condition", Types: []object.Type{object.BOOLEAN}},
+				message,
+			},
+			Doc: "Fails the test when the condition is false.",
+			Fun: builtAssert,
+		},
+		{
+			Name:   "assert_eq",
+			Params: []object.Param{{Name: "actual"}, {Name: "expected"}, message},
+			Doc:    "Fails the test when both values are not equal.",
+			Fun:    builtAssertEq,
+		},
+		{
+			Name: "assert_error",
+			Params: []object.Param{
+				{Name: "function", Types: []object.Type{object.FUNCTION, object.BUILTIN}},
+				{Name: "contains", Types: []object.Type{object.STRING}, Optional: true},
+			},
+			Doc: "Fails the test unless calling the function without arguments fails, with an error containing the given text if any.",
+			Fun: func(rt *object.Runtime, args ...object.Object) (object.Object, error) {
+				return nil, assertError(eval, args)
+			},
+		},
+	}
+	for _, builtin := range builtins {
+		_ = eval.Builtins.Register(builtin)
+	}
+}
+
+func builtAssert(rt *object.Runtime, args ...object.Object) (object.Object, error) {
+	if !args[0].(*object.Boolean).Value {
+		return nil, failure("assertion failed", args[1:])
+	}
+	return nil, nil
+}
+
+func builtAssertEq(rt *object.Runtime, args ...object.Object) (object.Object, error) {
+	if !object.Equals(args[0], args[1]) {
+		text := fmt.Sprintf("expected %s but got %s", object.Inspect(args[1]), object.Inspect(args[0]))
+		return nil, failure(text, args[2:])
+	}
+	return nil, nil
+}
+
+func assertError(eval *evaluator.Evaluator, args []object.Object) error {
+	_, errs := eval.Apply(args[0])
+	if errs.Len() == 0 {
+		return errors.New("expected an error but got none")
+	}
+	if len(args) < 2 {
+		return nil
+	}
+	contains := args[1].(*object.String).Value
+	for _, err := range errs {
+		if strings.Contains(err.Message(), contains) {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected an error containing %q but got %q", contains, errs[0].Message())
+}
+
+func failure(text string, message []object.Object) error {
+	if len(message) > 0 {
+		text = message[0].(*object.String).Value + ": " + text
+	}
+	return errors.New(text)
+}
@@ -0,0 +1,92 @@
+package tester
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the suites as a JUnit XML report, a suite
+// that couldn't run being reported as a single errored case.
+func WriteJUnit(w io.Writer, suites []*Suite) error {
+	var report junitSuites
+	for _, suite := range suites {
+		js := junitSuite{
+			Name:     suite.File,
+			Tests:    len(suite.Tests),
+			Failures: suite.Failures(),
+			Time:     seconds(suite.Duration.Seconds()),
+		}
+		if suite.Errors.Len() > 0 {
+			js.Tests, js.Errors = 1, 1
+			js.Cases = append(js.Cases, junitCase{
+				Name:      "setup",
+				ClassName: suite.File,
+				Time:      js.Time,
+				Error:     message(suite.Errors.String()),
+			})
+		}
+		for _, test := range suite.Tests {
+			jc := junitCase{
+				Name:      test.Name,
+				ClassName: suite.File,
+				Time:      seconds(test.Duration.Seconds()),
+				SystemOut: test.Output,
+			}
+			if !test.Passed() {
+				jc.Failure = message(test.Errors.String())
+			}
+			js.Cases = append(js.Cases, jc)
+		}
+		report.Suites = append(report.Suites, js)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func message(errors string) *junitMessage {
+	errors = strings.TrimSpace(errors)
+	first := strings.SplitN(errors, "\n", 2)[0]
+	return &junitMessage{Message: first, Text: errors}
+}
+
+func seconds(s float64) string {
+	return fmt.Sprintf("%.3f", s)
+}
@@ -0,0 +1,212 @@
+// Package tester runs the test functions of Eldr test files,
+// each one in a fresh evaluator.
+package tester
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/Onelio/Eldrlang/evaluator"
+	"github.com/Onelio/Eldrlang/object"
+	"github.com/Onelio/Eldrlang/parser"
+	"github.com/Onelio/Eldrlang/util"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	fileSuffix = "_test.eld"
+	testPrefix = "test_"
+)
+
+// Options apply to every test, Timeout bounding each one of
+// them when set.
+type Options struct {
+	Timeout time.Duration
+	Policy  *object.Policy
+}
+
+// Result is the outcome of a single test function.
+type Result struct {
+	Name     string
+	Duration time.Duration
+	Output   string
+	Errors   util.Errors
+}
+
+func (r *Result) Passed() bool {
+	return r.Errors.Len() == 0
+}
+
+// Suite holds the results of a test file. Errors are the ones
+// preventing the file from running at all, Warnings the ones
+// found parsing it that don't.
+type Suite struct {
+	File     string
+	Duration time.Duration
+	Errors   util.Errors
+	Warnings util.Errors
+	Tests    []*Result
+}
+
+func (s *Suite) Failures() int {
+	failures := 0
+	for _, test := range s.Tests {
+		if !test.Passed() {
+			failures++
+		}
+	}
+	return failures
+}
+
+func (s *Suite) Passed() bool {
+	return s.Errors.Len() == 0 && s.Failures() == 0
+}
+
+// Discover returns the test files given, and the ones found
+// within the directories given, sorted. Like with Go, nested
+// testdata and hidden directories are skipped.
+func Discover(paths ...string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			name := info.Name()
+			if info.IsDir() && file != path && (name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			if !info.IsDir() && strings.HasSuffix(name, fileSuffix) {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// RunFile runs every test function of a file. The library the
+// file tests, named like it without the suffix, is loaded
+// first when it exists.
+func RunFile(path string, opts Options) (*Suite, error) {
+	suite := &Suite{File: path}
+	start := time.Now()
+	defer func() { suite.Duration = time.Since(start) }()
+
+	var packages []*parser.Package
+	sources := []string{strings.TrimSuffix(path, fileSuffix) + ".eld", path}
+	for i, source := range sources {
+		code, err := ioutil.ReadFile(source)
+		if os.IsNotExist(err) && i == 0 {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		pkg := parser.NewParser().ParsePackage(string(code), "main")
+		if pkg.Errors.Len() > 0 {
+			suite.Errors = pkg.Errors
+			return suite, nil
+		}
+		suite.Warnings = append(suite.Warnings, pkg.Warnings...)
+		packages = append(packages, pkg)
+	}
+	for _, name := range testNames(packages[len(packages)-1]) {
+		suite.Tests = append(suite.Tests, runTest(packages, name, opts))
+	}
+	return suite, nil
+}
+
+func testNames(pkg *parser.Package) []string {
+	var names []string
+	for _, node := range pkg.Nodes {
+		fun, ok := node.(*parser.Function)
+		if ok && strings.HasPrefix(fun.Name.Value, testPrefix) && len(fun.Params) == 0 {
+			names = append(names, fun.Name.Value)
+		}
+	}
+	return names
+}
+
+func runTest(packages []*parser.Package, name string, opts Options) *Result {
+	var (
+		result = &Result{Name: name}
+		output bytes.Buffer
+		eval   = evaluator.NewEvaluator()
+	)
+	eval.Policy = opts.Policy
+	defer eval.Close()
+	eval.Stdin = strings.NewReader("")
+	eval.Stdout, eval.Stderr = &output, &output
+	registerAsserts(eval)
+
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	for _, pkg := range packages {
+		result.Errors = append(result.Errors, eval.EvaluateContext(ctx, pkg).Errors...)
+	}
+	if result.Errors.Len() == 0 {
+		result.Errors = eval.CallContext(ctx, name).Errors
+	}
+	result.Duration = time.Since(start)
+	result.Output = output.String()
+	return result
+}
+
+// Report writes the results of a suite the way go test does,
+// including the output of failed tests, or every test when
+// verbose.
+func Report(w io.Writer, suite *Suite, verbose bool) {
+	if suite.Errors.Len() > 0 {
+		_, _ = fmt.Fprintf(w, "FAIL\t%s [setup failed]\n", suite.File)
+		_, _ = fmt.Fprint(w, indent(suite.Errors.String()))
+		return
+	}
+	_, _ = fmt.Fprint(w, indent(suite.Warnings.String()))
+	for _, test := range suite.Tests {
+		status := "PASS"
+		if !test.Passed() {
+			status = "FAIL"
+		}
+		if verbose || !test.Passed() {
+			_, _ = fmt.Fprintf(w, "--- %s: %s (%.2fs)\n", status, test.Name, test.Duration.Seconds())
+			_, _ = fmt.Fprint(w, indent(test.Output+test.Errors.String()))
+		}
+	}
+	if suite.Passed() {
+		_, _ = fmt.Fprintf(w, "ok\t%s\t%.3fs\n", suite.File, suite.Duration.Seconds())
+	} else {
+		_, _ = fmt.Fprintf(w, "FAIL\t%s\t%.3fs\n", suite.File, suite.Duration.Seconds())
+	}
+}
+
+func indent(text string) string {
+	if text == "" {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	return "    " + strings.Join(lines, "\n    ") + "\n"
+}
@@ -0,0 +1,68 @@
+package tester
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunFile(t *testing.T) {
+	files, err := Discover("testdata")
+	if err != nil || len(files) != 1 || files[0] != "testdata/math_test.eld" {
+		t.Fatalf("unexpected test files %v (%v)", files, err)
+	}
+	suite, err := RunFile(files[0], Options{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		name   string
+		errors []string
+	}{
+		{"test_double", nil},
+		{"test_wrong", []string{
+			`"assert_eq" failed: expected "2" but got 2`,
+			`"assert" failed: never: assertion failed`,
+		}},
+		{"test_no_error", []string{`"assert_error" failed: expected an error but got none`}},
+		{"test_forever", []string{`evaluation cancelled: context deadline exceeded`}},
+	}
+	if len(suite.Tests) != len(tests) || suite.Failures() != 3 || suite.Passed() {
+		t.Fatalf("unexpected results %+v", suite.Tests)
+	}
+	for i, test := range tests {
+		result := suite.Tests[i]
+		if result.Name != test.name || result.Errors.Len() != len(test.errors) {
+			t.Fatalf("%s: unexpected result %s %s", test.name, result.Name, result.Errors.String())
+		}
+		for j, err := range test.errors {
+			if result.Errors[j].Message() != err {
+				t.Fatalf("%s: expected %q got %q", test.name, err, result.Errors[j].Message())
+			}
+		}
+	}
+	if suite.Tests[1].Output != "debugging 2" {
+		t.Fatalf("output not captured %q", suite.Tests[1].Output)
+	}
+
+	var report bytes.Buffer
+	Report(&report, suite, false)
+	if strings.Contains(report.String(), "test_double") || !strings.Contains(report.String(), "--- FAIL: test_wrong") {
+		t.Fatalf("unexpected report\n%s", report.String())
+	}
+	var junit bytes.Buffer
+	if err := WriteJUnit(&junit, []*Suite{suite}); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<testsuite name="testdata/math_test.eld" tests="4" failures="3" errors="0"`,
+		`<testcase name="test_double" classname="testdata/math_test.eld"`,
+		`<failure message="* Error at L8 &#34;assert_eq&#34; failed: expected &#34;2&#34; but got 2">`,
+		`<system-out>debugging 2</system-out>`,
+	} {
+		if !strings.Contains(junit.String(), expected) {
+			t.Fatalf("missing %q in\n%s", expected, junit.String())
+		}
+	}
+}
@@ -12,6 +12,7 @@ const (
 	ExpectedBrace = "expected opening brace but got \"%s\""
 	ExpectedCondV = "expected conditional or boolean"
 	ExpectedFuncP = "expected %d function parameters"
+	ExpectedSemic = "expected \";\" but got \"%s\""
 	UnexpectedEOF = "unexpected end of file, expected \"%s\""
 	UnexpectedBRC = "unexpected right brace, expected \"%s\""
 	InvalidNumber = "\"%s\" is not a valid number"
@@ -22,11 +23,43 @@ const (
 	IllegalExprBr = "illegal expresion declaration after break, expected \";\""
 	IdentNotFound = "identifier \"%s\" not found"
 	IdentNotAFunc = "identifier \"%s\" is not a function"
+	InvalidArgNum = "\"%s\" expects %s arguments but got %d"
+	InvalidArgTyp = "argument %d of \"%s\" expected %s but got %s"
+	BuiltinFailed = "\"%s\" failed: %s"
+	AccessDenied  = "permission denied: %s access to \"%s\""
+	LimitExceeded = "%s limit exceeded"
+	EvalCancelled = "evaluation cancelled: %s"
+	DividedByZero = "division by zero"
+	InternalError = "internal error: %v"
+	ExpectedValue = "expected value for constant \"%s\""
+	AssignToConst = "cannot assign to constant \"%s\""
+	DuplicateDecl = "\"%s\" is already declared"
+	ExpectedInKey = "expected \"in\" but got \"%s\""
+	NotIterableOb = "%s is not iterable"
+	OutsideOfLoop = "%s outside of a loop"
+	LabelNotFound = "label \"%s\" not found"
+	ExpectedLoopL = "expected loop after label \"%s\""
+	LabelReplaced = "label \"%s\" is already used by an enclosing loop"
+	ExpectedArrow = "expected \"=>\" but got \"%s\""
+	ExpectedPattn = "expected pattern but got \"%s\""
+	NoMatchingArm = "no pattern matches %s"
+	UnknownFieldN = "%s has no field \"%s\""
+	NotAStructTyp = "\"%s\" is not a struct type"
+	NotAClassType = "\"%s\" is not a class"
+	ExpectedMethd = "expected method but got \"%s\""
+	ExpectedDotOp = "expected \".\" but got \"%s\""
+	OutsideOfClas = "%s outside of a class"
+	NoParentClass = "class \"%s\" has no parent"
+	NotAnEnumType = "\"%s\" is not an enum"
+	UnknownVarian = "%s has no variant \"%s\""
+	NotExhaustive = "%s over %s misses %s"
+	OutsideOfFunc = "%s outside of a function"
 )
 
 type Error struct {
-	token lexer.Token
-	str   string
+	token   lexer.Token
+	str     string
+	warning bool
 }
 
 func NewError(t lexer.Token, f string, a ...interface{}) *Error {
@@ -36,9 +69,29 @@ func NewError(t lexer.Token, f string, a ...interface{}) *Error {
 	}
 }
 
+// NewWarning is NewError for issues that don't stop the code
+// from running.
+func NewWarning(t lexer.Token, f string, a ...interface{}) *Error {
+	err := NewError(t, f, a...)
+	err.warning = true
+	return err
+}
+
+func (e *Error) Token() lexer.Token {
+	return e.token
+}
+
+func (e *Error) Message() string {
+	return e.str
+}
+
 func (e *Error) String() string {
-	return fmt.Sprintf("* Error at L%d %s",
-		e.token.Line+1, e.str)
+	kind := "Error"
+	if e.warning {
+		kind = "Warning"
+	}
+	return fmt.Sprintf("* %s at L%d %s",
+		kind, e.token.Line+1, e.str)
 }
 
 type Errors []*Error
@@ -58,7 +111,7 @@ func (es *Errors) Clear() {
 func (es *Errors) String() string {
 	var out bytes.Buffer
 	for _, e := range *es {
-		_, _ = fmt.Println(&out, e.String())
+		_, _ = fmt.Fprintln(&out, e.String())
 	}
 	return out.String()
 }